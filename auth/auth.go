@@ -2,40 +2,187 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
 )
 
 const (
 	scope = "https://www.googleapis.com/auth/cloud-platform"
 )
 
-func NewClient(sa string) *http.Client {
-	var err error
-	var ts oauth2.TokenSource
-	ctx := context.Background()
-	if sa == "" {
-		ts, err = defaultTokenSource(ctx)
-	} else {
-		ts, err = impersonatedTokenSource(ctx, sa)
+type config struct {
+	credentialsFile string
+	credentialsJSON []byte
+	impersonate     string
+	delegates       []string
+	scopes          []string
+	quotaProject    string
+}
+
+// Configures how NewClient and NewTokenSource find credentials
+type Option func(*config)
+
+// Loads credentials from a JSON file: a service account key or an external
+// account (workload identity federation) configuration. It can't be combined
+// with WithCredentialsJSON.
+func WithCredentialsFile(filename string) Option {
+	return func(c *config) {
+		c.credentialsFile = filename
+	}
+}
+
+// Loads credentials from JSON: a service account key or an external account
+// (workload identity federation) configuration. It can't be combined with
+// WithCredentialsFile.
+func WithCredentialsJSON(credentialsJSON []byte) Option {
+	return func(c *config) {
+		c.credentialsJSON = credentialsJSON
+	}
+}
+
+// Impersonates the target service account, optionally through a chain of
+// delegate service accounts
+func WithImpersonation(targetPrincipal string, delegates ...string) Option {
+	return func(c *config) {
+		c.impersonate = targetPrincipal
+		c.delegates = delegates
+	}
+}
+
+// Requests tokens with these scopes instead of the cloud-platform scope
+func WithScopes(scopes ...string) Option {
+	return func(c *config) {
+		c.scopes = scopes
+	}
+}
+
+// Bills API quota to this project with the x-goog-user-project header
+func WithQuotaProject(project string) Option {
+	return func(c *config) {
+		c.quotaProject = project
+	}
+}
+
+// Creates an authenticated http client. Without options it uses Application
+// Default Credentials.
+//
+// The context is used to create tokens for the lifetime of the client.
+func NewClient(ctx context.Context, opts ...Option) (*http.Client, error) {
+	cfg := newConfig(opts)
+	ts, err := cfg.tokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var base http.RoundTripper
+	if client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		base = client.Transport
+	}
+	return &http.Client{Transport: cfg.transport(ts, base)}, nil
+}
+
+// Creates a token source from the options, see NewClient
+func NewTokenSource(ctx context.Context, opts ...Option) (oauth2.TokenSource, error) {
+	return newConfig(opts).tokenSource(ctx)
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		scopes: []string{scope},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// Returns a transport that authenticates requests with the token source and
+// sets the quota project header, sending them with the base transport
+func (c *config) transport(ts oauth2.TokenSource, base http.RoundTripper) http.RoundTripper {
+	var transport http.RoundTripper = &oauth2.Transport{
+		Source: oauth2.ReuseTokenSource(nil, ts),
+		Base:   base,
+	}
+	if c.quotaProject != "" {
+		transport = &quotaProjectTransport{
+			base:         transport,
+			quotaProject: c.quotaProject,
+		}
+	}
+	return transport
+}
+
+func (c *config) tokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	if c.impersonate == "" {
+		return c.baseTokenSource(ctx, c.scopes)
+	}
+	// the impersonating principal needs the cloud-platform scope to call the IAM credentials API
+	base, err := c.baseTokenSource(ctx, []string{scope})
+	if err != nil {
+		return nil, err
+	}
+	return impersonatedTokenSource(ctx, base, c.impersonate, c.delegates, c.scopes)
+}
+
+func (c *config) baseTokenSource(ctx context.Context, scopes []string) (oauth2.TokenSource, error) {
+	if c.credentialsFile != "" && c.credentialsJSON != nil {
+		return nil, errors.New("both a credentials file and credentials JSON are configured, use only one of them")
+	}
+	credentialsJSON := c.credentialsJSON
+	if c.credentialsFile != "" {
+		var err error
+		credentialsJSON, err = os.ReadFile(c.credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading credentials file: %w", err)
+		}
 	}
+	if credentialsJSON == nil {
+		return defaultTokenSource(ctx, scopes)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, credentialsJSON, scopes...)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("loading credentials: %w", err)
 	}
-	return oauth2.NewClient(ctx, ts)
+	return creds.TokenSource, nil
 }
 
-func defaultTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
-	return google.DefaultTokenSource(ctx, scope)
+func defaultTokenSource(ctx context.Context, scopes []string) (oauth2.TokenSource, error) {
+	ts, err := google.DefaultTokenSource(ctx, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("finding default credentials: %w", err)
+	}
+	return ts, nil
 }
 
-func impersonatedTokenSource(ctx context.Context, sa string) (oauth2.TokenSource, error) {
+func impersonatedTokenSource(ctx context.Context, base oauth2.TokenSource, sa string, delegates, scopes []string) (oauth2.TokenSource, error) {
 	cfg := impersonate.CredentialsConfig{
-		TargetPrincipal: string(sa),
-		Scopes:          []string{scope},
+		TargetPrincipal: sa,
+		Delegates:       delegates,
+		Scopes:          scopes,
 	}
-	return impersonate.CredentialsTokenSource(ctx, cfg)
+	// call the IAM credentials API with the context's http client, like token
+	// requests of the base credentials
+	ts, err := impersonate.CredentialsTokenSource(ctx, cfg, option.WithHTTPClient(oauth2.NewClient(ctx, base)))
+	if err != nil {
+		return nil, fmt.Errorf("impersonating %s: %w", sa, err)
+	}
+	return ts, nil
+}
+
+// Sets the x-goog-user-project header on every request
+type quotaProjectTransport struct {
+	base         http.RoundTripper
+	quotaProject string
+}
+
+func (t *quotaProjectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Goog-User-Project", t.quotaProject)
+	return t.base.RoundTrip(req)
 }
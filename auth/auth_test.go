@@ -0,0 +1,101 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Writes a service account key whose token endpoint is the test server
+func serviceAccountKey(t *testing.T, tokenURL string) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	keyJSON, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "testproject",
+		"private_key_id": "1234",
+		"private_key":    string(keyPEM),
+		"client_email":   "test@testproject.iam.gserviceaccount.com",
+		"token_uri":      tokenURL,
+	})
+	require.NoError(t, err)
+	return keyJSON
+}
+
+func TestNewClient(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "testtoken", "token_type": "Bearer", "expires_in": 3600}`)
+			return
+		}
+		headers = r.Header
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	keyJSON := serviceAccountKey(t, server.URL+"/token")
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+
+	tt := []struct {
+		name                 string
+		opts                 []auth.Option
+		expectedQuotaProject string
+	}{
+		{
+			name: "Credentials JSON",
+			opts: []auth.Option{auth.WithCredentialsJSON(keyJSON)},
+		},
+		{
+			name:                 "Credentials file with quota project",
+			opts:                 []auth.Option{auth.WithCredentialsFile(keyFile), auth.WithQuotaProject("billingproject"), auth.WithScopes("https://www.googleapis.com/auth/chronicle-backstory")},
+			expectedQuotaProject: "billingproject",
+		},
+	}
+	for _, tt := range tt {
+		headers = nil
+		client, err := auth.NewClient(ctx, tt.opts...)
+		require.NoError(t, err, tt.name)
+		resp, err := client.Get(server.URL + "/v1alpha/projects")
+		require.NoError(t, err, tt.name)
+		resp.Body.Close()
+		assert.Equal(t, "Bearer testtoken", headers.Get("Authorization"), tt.name)
+		assert.Equal(t, tt.expectedQuotaProject, headers.Get("X-Goog-User-Project"), tt.name)
+	}
+}
+
+func TestNewClientErrors(t *testing.T) {
+	ctx := context.Background()
+	_, err := auth.NewClient(ctx, auth.WithCredentialsFile(filepath.Join(t.TempDir(), "missing.json")))
+	assert.Error(t, err)
+	_, err = auth.NewClient(ctx, auth.WithCredentialsJSON([]byte(`not json`)))
+	assert.Error(t, err)
+	_, err = auth.NewTokenSource(ctx, auth.WithCredentialsJSON([]byte(`{"type": "unknown"}`)))
+	assert.Error(t, err)
+
+	keyJSON := serviceAccountKey(t, "http://localhost/token")
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+	_, err = auth.NewClient(ctx, auth.WithCredentialsFile(keyFile), auth.WithCredentialsJSON(keyJSON))
+	assert.ErrorContains(t, err, "use only one of them")
+	_, err = auth.NewTokenSource(ctx, auth.WithCredentialsJSON(keyJSON), auth.WithCredentialsFile(keyFile), auth.WithImpersonation("target@testproject.iam.gserviceaccount.com"))
+	assert.ErrorContains(t, err, "use only one of them")
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"golang.org/x/oauth2"
+)
+
+// An http.RoundTripper that authenticates each request with the principal
+// routed to the Chronicle instance (or project) in the request URL, so one
+// http client can operate across instances that each need a different
+// service account.
+//
+// Principals are service account emails impersonated with the router's base
+// credentials, unless SetTokenSource registers a token source for them.
+// Token sources and transports are created on first use and cached per
+// principal. Requests that match no route use the base credentials. Options
+// of the base credentials such as WithQuotaProject apply to every principal.
+type Router struct {
+	ctx      context.Context
+	baseOpts []Option
+	// The transport that sends authenticated requests, http.DefaultTransport if nil
+	Base http.RoundTripper
+
+	mu         sync.Mutex
+	instances  map[string]string
+	projects   map[string]string
+	sources    map[string]oauth2.TokenSource
+	transports map[string]*principalTransport
+}
+
+// The transport of a principal, created once on first use without holding
+// the router lock since finding credentials can read files or call the
+// metadata server
+type principalTransport struct {
+	once      sync.Once
+	transport http.RoundTripper
+	err       error
+}
+
+// Creates a router whose base credentials are found with the options, see
+// NewClient. The context is used to create tokens for the lifetime of the router.
+func NewRouter(ctx context.Context, opts ...Option) *Router {
+	return &Router{
+		ctx:        ctx,
+		baseOpts:   opts,
+		instances:  map[string]string{},
+		projects:   map[string]string{},
+		sources:    map[string]oauth2.TokenSource{},
+		transports: map[string]*principalTransport{},
+	}
+}
+
+// Routes requests for resources of the instance to the principal
+func (r *Router) RouteInstance(instance resources.ResourcePath, principal string) error {
+	instancePath := instance.Instance()
+	if instancePath.IsEmpty() {
+		return fmt.Errorf("invalid instance %q", instance.String())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances[instancePath.String()] = principal
+	return nil
+}
+
+// Routes requests for every instance of the project to the principal.
+// Instance routes take precedence over project routes.
+func (r *Router) RouteProject(project, principal string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.projects[project] = principal
+}
+
+// Uses the token source for the principal instead of impersonating it
+func (r *Router) SetTokenSource(principal string, ts oauth2.TokenSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[principal] = ts
+	delete(r.transports, principal)
+}
+
+// Returns an http client that routes every request through the router
+func (r *Router) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Router) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport, err := r.transport(r.principal(req.URL.Path))
+	if err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+// Returns the principal routed to the instance in a request URL path, ""
+// for the base credentials
+func (r *Router) principal(urlPath string) string {
+	instance, project := instanceFromURLPath(urlPath)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if principal, ok := r.instances[instance]; ok {
+		return principal
+	}
+	return r.projects[project]
+}
+
+func (r *Router) transport(principal string) (http.RoundTripper, error) {
+	r.mu.Lock()
+	pt, ok := r.transports[principal]
+	if !ok {
+		pt = &principalTransport{}
+		r.transports[principal] = pt
+	}
+	ts, hasSource := r.sources[principal]
+	r.mu.Unlock()
+
+	pt.once.Do(func() {
+		if !hasSource {
+			opts := r.baseOpts
+			if principal != "" {
+				opts = append(opts[:len(opts):len(opts)], WithImpersonation(principal))
+			}
+			ts, pt.err = NewTokenSource(r.ctx, opts...)
+		}
+		if pt.err == nil {
+			pt.transport = newConfig(r.baseOpts).transport(ts, r.Base)
+		}
+	})
+	if pt.err != nil {
+		// retry failed lookups on the next request
+		r.mu.Lock()
+		if r.transports[principal] == pt {
+			delete(r.transports, principal)
+		}
+		r.mu.Unlock()
+		return nil, pt.err
+	}
+	return pt.transport, nil
+}
+
+// Finds the projects/{project}/locations/{location}/instances/{instance}
+// prefix in a request URL path, ex. /v1alpha/projects/...
+func instanceFromURLPath(urlPath string) (string, string) {
+	_, name, ok := strings.Cut(urlPath, resources.ProjectsResourceName+"/")
+	if !ok {
+		return "", ""
+	}
+	elements := strings.SplitN(resources.ProjectsResourceName+"/"+name, "/", 7)
+	project := elements[1]
+	if len(elements) < 6 {
+		return "", project
+	}
+	// strip a custom method verb from the instance ID, ex. instances/{instance}:udmSearch
+	elements[5], _, _ = strings.Cut(elements[5], ":")
+	instance, err := resources.ParseResourcePath(strings.Join(elements[:6], "/"))
+	if err != nil {
+		return "", project
+	}
+	return instance.String(), project
+}
@@ -0,0 +1,143 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calebryant/chronicle-api/auth"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// Counts how many times a token is requested
+type countingTokenSource struct {
+	token string
+	calls int
+}
+
+func (s *countingTokenSource) Token() (*oauth2.Token, error) {
+	s.calls++
+	return &oauth2.Token{AccessToken: s.token}, nil
+}
+
+func TestRouter(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	router := auth.NewRouter(context.Background())
+	router.Base = server.Client().Transport
+	customerA := &countingTokenSource{token: "customer-a"}
+	customerB := &countingTokenSource{token: "customer-b"}
+	base := &countingTokenSource{token: "base"}
+	router.SetTokenSource("a@mssp.iam.gserviceaccount.com", customerA)
+	router.SetTokenSource("b@mssp.iam.gserviceaccount.com", customerB)
+	router.SetTokenSource("", base)
+	require.NoError(t, router.RouteInstance(resources.NewResourcePath("project-a", "us", "instance-a"), "a@mssp.iam.gserviceaccount.com"))
+	router.RouteProject("project-b", "b@mssp.iam.gserviceaccount.com")
+	assert.Error(t, router.RouteInstance(resources.ResourcePath{}, "a@mssp.iam.gserviceaccount.com"))
+
+	tt := []struct {
+		name          string
+		path          string
+		expectedToken string
+	}{
+		{
+			name:          "Instance route",
+			path:          "/v1alpha/projects/project-a/locations/us/instances/instance-a/logTypes/WINEVTLOG",
+			expectedToken: "customer-a",
+		},
+		{
+			name:          "Instance route with custom verb",
+			path:          "/v1alpha/projects/project-a/locations/us/instances/instance-a:udmSearch",
+			expectedToken: "customer-a",
+		},
+		{
+			name:          "Project route",
+			path:          "/v1alpha/projects/project-b/locations/eu/instances/instance-b",
+			expectedToken: "customer-b",
+		},
+		{
+			name:          "Unrouted instance",
+			path:          "/v1alpha/projects/project-a/locations/us/instances/instance-c",
+			expectedToken: "base",
+		},
+		{
+			name:          "Instance route again",
+			path:          "/v1alpha/projects/project-a/locations/us/instances/instance-a/logTypes/WINEVTLOG/parsers",
+			expectedToken: "customer-a",
+		},
+	}
+	client := router.Client()
+	for _, tt := range tt {
+		resp, err := client.Get(server.URL + tt.path)
+		require.NoError(t, err, tt.name)
+		resp.Body.Close()
+		assert.Equal(t, "Bearer "+tt.expectedToken, authorization, tt.name)
+	}
+	// tokens are cached per principal
+	assert.Equal(t, 1, customerA.calls)
+	assert.Equal(t, 1, customerB.calls)
+	assert.Equal(t, 1, base.calls)
+}
+
+// Sends every request to the test server, ex. IAM credentials API calls
+type redirectTransport struct {
+	server *httptest.Server
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(t.server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return t.server.Client().Transport.RoundTrip(req)
+}
+
+func TestRouterImpersonation(t *testing.T) {
+	var headers http.Header
+	var impersonatedWith string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/token":
+			fmt.Fprint(w, `{"access_token": "base", "token_type": "Bearer", "expires_in": 3600}`)
+		case strings.HasSuffix(r.URL.Path, ":generateAccessToken"):
+			impersonatedWith = r.Header.Get("Authorization")
+			principal := strings.TrimSuffix(path.Base(r.URL.Path), ":generateAccessToken")
+			fmt.Fprintf(w, `{"accessToken": "impersonated-%s", "expireTime": %q}`, principal, time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			headers = r.Header
+		}
+	}))
+	defer server.Close()
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: &redirectTransport{server: server}})
+
+	router := auth.NewRouter(ctx, auth.WithCredentialsJSON(serviceAccountKey(t, server.URL+"/token")), auth.WithQuotaProject("billingproject"))
+	router.Base = server.Client().Transport
+	require.NoError(t, router.RouteInstance(resources.NewResourcePath("project-a", "us", "instance-a"), "a@mssp.iam.gserviceaccount.com"))
+	client := router.Client()
+
+	resp, err := client.Get(server.URL + "/v1alpha/projects/project-a/locations/us/instances/instance-a/logTypes")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer impersonated-a@mssp.iam.gserviceaccount.com", headers.Get("Authorization"))
+	assert.Equal(t, "billingproject", headers.Get("X-Goog-User-Project"))
+	assert.Equal(t, "Bearer base", impersonatedWith)
+
+	resp, err = client.Get(server.URL + "/v1alpha/projects/project-b/locations/us/instances/instance-b/logTypes")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer base", headers.Get("Authorization"))
+	assert.Equal(t, "billingproject", headers.Get("X-Goog-User-Project"))
+}
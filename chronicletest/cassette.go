@@ -0,0 +1,337 @@
+package chronicletest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources"
+)
+
+// Headers scrubbed from recorded interactions by default
+var DefaultScrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "X-Goog-Api-Key", "Cookie", "Set-Cookie"}
+
+// Returned by a replaying Recorder for a request that matches no recorded
+// interaction
+var ErrUnmatchedRequest = errors.New("chronicletest: no recorded interaction matches the request")
+
+// Whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// Serve responses from the cassette file, never touching the network
+	Replay Mode = iota
+	// Send requests with the base transport and record every interaction
+	Record
+)
+
+// An http.RoundTripper that records Chronicle API interactions to a cassette
+// file and replays them offline, ex. to capture real interactions once and
+// replay them in CI:
+//
+//	mode := chronicletest.Replay
+//	if *record {
+//		mode = chronicletest.Record
+//	}
+//	recorder, err := chronicletest.NewRecorder("testdata/parsers.json", mode, authClient.Transport)
+//	defer recorder.Save()
+//	client, err := chronicleapi.NewClient(recorder.Client(), endpoint)
+//
+// Interactions are matched by HTTP method, resource name (with any custom
+// method verb), query and normalized JSON body, so the API version of the
+// endpoint and JSON key order do not matter. Identical requests are replayed
+// in the order they were recorded.
+//
+// Scrubbed headers and JSON fields are replaced before interactions are
+// written, and request bodies are scrubbed the same way before they are
+// matched during replay.
+type Recorder struct {
+	// The transport that sends requests while recording, http.DefaultTransport if nil
+	Base http.RoundTripper
+	// Header names whose values are scrubbed
+	ScrubHeaders []string
+	// JSON field names whose values are scrubbed from bodies at any depth, ex. "cbn"
+	ScrubFields []string
+
+	filename string
+	mode     Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// A recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// The resource name and custom method verb, ex. projects/.../parsers/{parser}:activate
+	Resource string          `json:"resource"`
+	Query    string          `json:"query,omitempty"`
+	Header   http.Header     `json:"header,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	// A body that is not JSON
+	BodyText string `json:"bodyText,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	// A body that is not JSON
+	BodyText string `json:"bodyText,omitempty"`
+}
+
+type cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Creates a recorder of the cassette file. A replaying recorder loads the
+// file, which must exist.
+func NewRecorder(filename string, mode Mode, base http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		Base:         base,
+		ScrubHeaders: DefaultScrubbedHeaders,
+		filename:     filename,
+		mode:         mode,
+	}
+	if mode == Record {
+		return r, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", filename, err)
+	}
+	for _, interaction := range c.Interactions {
+		// cassettes are indented, recorded requests are matched compact
+		var body bytes.Buffer
+		if err := json.Compact(&body, interaction.Request.Body); err == nil {
+			interaction.Request.Body = body.Bytes()
+		}
+	}
+	r.interactions = c.Interactions
+	r.replayed = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Returns an http client that sends every request through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, req, err := r.recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == Replay {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return r.replay(req, recorded)
+	}
+
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recordedResp := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     r.scrubHeader(resp.Header),
+	}
+	recordedResp.Body, recordedResp.BodyText = r.scrubBody(body)
+	r.interactions = append(r.interactions, &Interaction{
+		Request:  *recorded,
+		Response: recordedResp,
+	})
+	return resp, nil
+}
+
+// Writes the recorded interactions to the cassette file. It does nothing
+// when replaying.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.filename), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.filename, append(data, '\n'), 0o644)
+}
+
+// Returns the recorded interactions that were not replayed, ex. to check
+// that a test made every call it made when it was recorded
+func (r *Recorder) Unused() []*Interaction {
+	if r.mode != Replay {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []*Interaction
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		r.replayed[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		// a scrubbed body is not as long as the recorded one
+		header.Del("Content-Length")
+		body := []byte(interaction.Response.Body)
+		if interaction.Response.BodyText != "" {
+			body = []byte(interaction.Response.BodyText)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w in %s: %s %s?%s %s%s", ErrUnmatchedRequest, r.filename, recorded.Method, recorded.Resource, recorded.Query, recorded.Body, recorded.BodyText)
+}
+
+func (recorded *RecordedRequest) matches(other *RecordedRequest) bool {
+	return recorded.Method == other.Method &&
+		recorded.Resource == other.Resource &&
+		recorded.Query == other.Query &&
+		bytes.Equal(recorded.Body, other.Body) &&
+		recorded.BodyText == other.BodyText
+}
+
+// Returns the scrubbed and normalized request and the request to send. The
+// body is read through GetBody when it is set, otherwise the body is read and
+// a clone of the request carries a copy of it, so the caller's request is
+// never modified.
+func (r *Recorder) recordRequest(req *http.Request) (*RecordedRequest, *http.Request, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if req.GetBody != nil {
+			body, err = readBody(req.GetBody)
+		} else {
+			body, err = io.ReadAll(req.Body)
+			req.Body.Close()
+			req = req.Clone(req.Context())
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	recorded := &RecordedRequest{
+		Method:   req.Method,
+		Resource: resourceName(req.URL.Path),
+		Query:    req.URL.Query().Encode(),
+		Header:   r.scrubHeader(req.Header),
+	}
+	recorded.Body, recorded.BodyText = r.scrubBody(body)
+	return recorded, req, nil
+}
+
+// Reads a copy of a request body
+func readBody(getBody func() (io.ReadCloser, error)) ([]byte, error) {
+	body, err := getBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// Returns the resource name and custom method verb of a request URL path
+// without the API version, or the path itself if it names no resource
+func resourceName(urlPath string) string {
+	version, name, ok := strings.Cut(strings.TrimPrefix(urlPath, "/"), "/")
+	if !ok || !slices.Contains(chronicleapi.APIVersions, chronicleapi.APIVersion(version)) {
+		return urlPath
+	}
+	name, verb, hasVerb := strings.Cut(name, ":")
+	path, err := resources.ParseResourcePath(name)
+	if err != nil {
+		return urlPath
+	}
+	if hasVerb {
+		return path.String() + ":" + verb
+	}
+	return path.String()
+}
+
+func (r *Recorder) scrubHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	scrubbedHeader := header.Clone()
+	for name := range scrubbedHeader {
+		if slices.ContainsFunc(r.ScrubHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+			scrubbedHeader[name] = []string{chronicleapi.Redacted}
+		}
+	}
+	return scrubbedHeader
+}
+
+// Returns the body as compact JSON with sorted keys and scrubbed fields, or
+// as text if it is not JSON
+func (r *Recorder) scrubBody(body []byte) (json.RawMessage, string) {
+	normalized, err := chronicleapi.RedactJSON(body, r.ScrubFields)
+	if err != nil {
+		return nil, string(body)
+	}
+	return normalized, ""
+}
@@ -0,0 +1,156 @@
+package chronicletest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "testdata", "parsers.json")
+	server := chronicletest.NewServer()
+	require.NoError(t, server.Add(logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")))
+	ctx := context.Background()
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	parser := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "")
+	parser.Cbn = []byte("secret cbn")
+
+	recorder, err := chronicletest.NewRecorder(filename, chronicletest.Record, server.Client().Transport)
+	require.NoError(t, err)
+	recorder.ScrubFields = []string{"cbn"}
+	client, err := chronicleapi.NewClient(recorder.Client(), server.Endpoint(), chronicleapi.WithMiddleware(
+		chronicleapi.HeaderMiddleware(http.Header{"Authorization": {"Bearer secrettoken"}}),
+	))
+	require.NoError(t, err)
+	_, err = client.LogTypes().Get(ctx, logtype)
+	require.NoError(t, err)
+	created, err := client.Parsers().Create(ctx, parser)
+	require.NoError(t, err)
+	_, err = client.LogTypes().Get(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "MISSING"))
+	require.True(t, chronicleapi.IsNotFound(err))
+	require.NoError(t, recorder.Save())
+	server.Close()
+
+	cassette, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.NotContains(t, string(cassette), "secrettoken")
+	assert.NotContains(t, string(cassette), "c2VjcmV0IGNibg==")
+	assert.Contains(t, string(cassette), "REDACTED")
+
+	// replay against the v1 endpoint of a server that no longer exists
+	recorder, err = chronicletest.NewRecorder(filename, chronicletest.Replay, nil)
+	require.NoError(t, err)
+	recorder.ScrubFields = []string{"cbn"}
+	endpoint, err := chronicleapi.NewCustomServiceEndpoint(server.URL, "v1")
+	require.NoError(t, err)
+	client, err = chronicleapi.NewClient(recorder.Client(), endpoint, chronicleapi.WithAPIVersions(chronicleapi.V1Alpha))
+	require.NoError(t, err)
+
+	replayed, err := client.LogTypes().Get(ctx, logtype)
+	require.NoError(t, err)
+	assert.Equal(t, logtype.Name, replayed.Name)
+	replayedParser, err := client.Parsers().Create(ctx, parser)
+	require.NoError(t, err)
+	assert.Equal(t, created.Name, replayedParser.Name)
+	assert.Len(t, recorder.Unused(), 1)
+	_, err = client.LogTypes().Get(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "MISSING"))
+	assert.True(t, chronicleapi.IsNotFound(err))
+	assert.Empty(t, recorder.Unused())
+
+	// every interaction has been replayed
+	_, err = client.LogTypes().Get(ctx, logtype)
+	assert.ErrorIs(t, err, chronicletest.ErrUnmatchedRequest)
+	other := parsers.NewParserResource("testproject", "us", "testinstance", "PAN_FIREWALL", "")
+	other.Cbn = []byte("secret cbn")
+	_, err = client.Parsers().Create(ctx, other)
+	assert.ErrorIs(t, err, chronicletest.ErrUnmatchedRequest)
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	_, err := chronicletest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), chronicletest.Replay, nil)
+	assert.Error(t, err)
+}
+
+func TestRecorderContentLength(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "content-length.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"name": "parser1", "cbn": "c2VjcmV0IGNibg=="}`)
+	}))
+	defer server.Close()
+	url := server.URL + "/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers/parser1"
+
+	recorder, err := chronicletest.NewRecorder(filename, chronicletest.Record, server.Client().Transport)
+	require.NoError(t, err)
+	recorder.ScrubFields = []string{"cbn"}
+	resp, err := recorder.Client().Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NotEmpty(t, resp.Header.Get("Content-Length"))
+	require.NoError(t, recorder.Save())
+
+	recorder, err = chronicletest.NewRecorder(filename, chronicletest.Replay, nil)
+	require.NoError(t, err)
+	resp, err = recorder.Client().Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "REDACTED")
+	assert.Empty(t, resp.Header.Get("Content-Length"))
+	assert.Equal(t, int64(len(body)), resp.ContentLength)
+}
+
+// Records the requests it is sent
+type sentRequests struct {
+	requests []*http.Request
+	bodies   []string
+}
+
+func (s *sentRequests) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	s.requests = append(s.requests, req)
+	s.bodies = append(s.bodies, string(body))
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+}
+
+func TestRecorderRequestBody(t *testing.T) {
+	sent := &sentRequests{}
+	recorder, err := chronicletest.NewRecorder(filepath.Join(t.TempDir(), "body.json"), chronicletest.Record, sent)
+	require.NoError(t, err)
+
+	// without GetBody the body is sent on a clone of the request
+	body := io.NopCloser(strings.NewReader(`{"name": "parser1"}`))
+	req, err := http.NewRequest(http.MethodPost, "https://chronicle.googleapis.com/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers", body)
+	require.NoError(t, err)
+	_, err = recorder.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, body, req.Body)
+	assert.NotSame(t, req, sent.requests[0])
+	assert.Equal(t, `{"name": "parser1"}`, sent.bodies[0])
+
+	// with GetBody the request is sent as is
+	req, err = http.NewRequest(http.MethodPost, "https://chronicle.googleapis.com/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers", strings.NewReader(`{"name": "parser2"}`))
+	require.NoError(t, err)
+	_, err = recorder.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Same(t, req, sent.requests[1])
+	assert.Equal(t, `{"name": "parser2"}`, sent.bodies[1])
+	require.NoError(t, recorder.Save())
+}
@@ -0,0 +1,388 @@
+package chronicletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+)
+
+// API methods served by the fake, keyed by method name
+var handlers = map[string]handlerFunc{
+	"logTypes.get":       getResource,
+	"logTypes.list":      listResources,
+	"logTypes.runParser": runParser,
+
+	"parsers.get":        getResource,
+	"parsers.list":       listResources,
+	"parsers.create":     createParser,
+	"parsers.delete":     deleteParser,
+	"parsers.copy":       copyParser,
+	"parsers.activate":   activateParser,
+	"parsers.deactivate": deactivateParser,
+
+	"parserExtensions.get":      getResource,
+	"parserExtensions.list":     listResources,
+	"parserExtensions.create":   createParserExtension,
+	"parserExtensions.delete":   deleteResource,
+	"parserExtensions.activate": activateParserExtension,
+
+	"validationReports.get": getResource,
+	"parsingErrors.list":    listResources,
+
+	"logs.list": listResources,
+
+	"rules.get":    getResource,
+	"rules.list":   listResources,
+	"rules.create": createRule,
+	"rules.patch":  patchRule,
+	"rules.delete": deleteResource,
+
+	"referenceLists.get":    getResource,
+	"referenceLists.list":   listResources,
+	"referenceLists.create": createReferenceList,
+	"referenceLists.patch":  patchResource,
+
+	"operations.get":    getResource,
+	"operations.list":   listResources,
+	"operations.cancel": cancelOperation,
+	"operations.delete": deleteResource,
+}
+
+func getResource(s *Server, c *call) (interface{}, *resources.Status) {
+	return s.lookup(c)
+}
+
+// Lists a collection in a response object with the items under the
+// collection name, ex. {"parsers": [...], "nextPageToken": "..."}
+func listResources(s *Server, c *call) (interface{}, *resources.Status) {
+	items, status := filter(c, s.children(c.path))
+	if status != nil {
+		return nil, status
+	}
+	page, nextPageToken, status := paginate(c, items)
+	if status != nil {
+		return nil, status
+	}
+	resp := object{}
+	if len(page) > 0 {
+		resp[c.path.Collection()] = page
+	}
+	if nextPageToken != "" {
+		resp["nextPageToken"] = nextPageToken
+	}
+	return resp, nil
+}
+
+func deleteResource(s *Server, c *call) (interface{}, *resources.Status) {
+	if _, status := s.lookup(c); status != nil {
+		return nil, status
+	}
+	s.remove(c.path.String())
+	return object{}, nil
+}
+
+// Updates the fields of a resource listed in the updateMask query parameter,
+// or every field in the request body if there is no mask
+func patchResource(s *Server, c *call) (interface{}, *resources.Status) {
+	obj, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	patch, status := decodeBody(c)
+	if status != nil {
+		return nil, status
+	}
+	fields := []string{}
+	if mask := c.query.Get("updateMask"); mask != "" {
+		fields = strings.Split(mask, ",")
+	} else {
+		for field := range patch {
+			fields = append(fields, field)
+		}
+	}
+	for _, field := range fields {
+		if field == "name" {
+			continue
+		}
+		if value, ok := patch[field]; ok {
+			obj[field] = value
+		} else {
+			delete(obj, field)
+		}
+	}
+	return obj, nil
+}
+
+// Parses every log of the request with the server's ParseFunc
+func runParser(s *Server, c *call) (interface{}, *resources.Status) {
+	var req struct {
+		Parser struct {
+			Cbn []byte `json:"cbn"`
+		} `json:"parser"`
+		ParserExtension struct {
+			CbnSnippet []byte `json:"cbnSnippet"`
+		} `json:"parserExtension"`
+		Log              [][]byte `json:"log"`
+		StatedumpAllowed bool     `json:"statedumpAllowed"`
+	}
+	if err := json.Unmarshal(c.body, &req); err != nil {
+		return nil, invalidArgument(fmt.Sprintf("invalid request body: %v", err))
+	}
+	if len(req.Parser.Cbn) == 0 {
+		return nil, invalidArgument("parser cbn is required")
+	}
+	resp := logtypes.RunParserResponse{}
+	for _, log := range req.Log {
+		resp.RunParserResults = append(resp.RunParserResults, s.parse(req.Parser.Cbn, req.ParserExtension.CbnSnippet, log, req.StatedumpAllowed))
+	}
+	return resp, nil
+}
+
+// The default ParseFunc, parses a log into a GENERIC_EVENT
+func genericParse(cbn, cbnSnippet, log []byte, statedumpAllowed bool) logtypes.RunParserResult {
+	event := logtypes.UDM{
+		"metadata": map[string]interface{}{
+			"eventType":   "GENERIC_EVENT",
+			"description": string(log),
+		},
+	}
+	result := logtypes.RunParserResult{
+		ParsedEvents: &logtypes.ParsedEvents{Events: []logtypes.EventOrEntity{{Event: event}}},
+	}
+	if statedumpAllowed {
+		statedump, _ := json.Marshal(map[string]string{"message": string(log)})
+		result.StatedumpResults = []logtypes.Statedump{{StatedumpResult: string(statedump)}}
+	}
+	return result
+}
+
+// Creates a parser with a generated ID. The parser starts inactive, see
+// activateParser.
+func createParser(s *Server, c *call) (interface{}, *resources.Status) {
+	obj, status := decodeBody(c)
+	if status != nil {
+		return nil, status
+	}
+	if cbn, _ := obj["cbn"].(string); cbn == "" {
+		return nil, invalidArgument("parser cbn is required")
+	}
+	name := c.path.Parent().Child(resources.ParsersResourceName, newID(resources.ParsersResourceName))
+	obj["name"] = name.String()
+	obj["type"] = "CUSTOM"
+	obj["state"] = "INACTIVE"
+	obj["createTime"] = now()
+	s.put(name.String(), obj)
+	return obj, nil
+}
+
+// Deletes a parser, an active parser is only deleted with force=true
+func deleteParser(s *Server, c *call) (interface{}, *resources.Status) {
+	parser, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	if parser["state"] == "ACTIVE" && c.query.Get("force") != "true" {
+		return nil, failedPrecondition(fmt.Sprintf("%s is active, delete it with force", c.path.String()))
+	}
+	s.remove(c.path.String())
+	return object{}, nil
+}
+
+// Copies a parser into a new inactive custom parser of the same log type
+func copyParser(s *Server, c *call) (interface{}, *resources.Status) {
+	parser, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	name := c.path.Parent().Child(resources.ParsersResourceName, newID(resources.ParsersResourceName))
+	parserCopy := object{
+		"name":       name.String(),
+		"cbn":        parser["cbn"],
+		"type":       "CUSTOM",
+		"state":      "INACTIVE",
+		"createTime": now(),
+	}
+	s.put(name.String(), parserCopy)
+	return parserCopy, nil
+}
+
+// Activates a parser and deactivates the log type's other active parser,
+// a log type has at most one active parser
+func activateParser(s *Server, c *call) (interface{}, *resources.Status) {
+	parser, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	for _, other := range s.children(c.path.WithoutID()) {
+		if other["state"] == "ACTIVE" {
+			other["state"] = "INACTIVE"
+		}
+	}
+	parser["state"] = "ACTIVE"
+	return object{}, nil
+}
+
+func deactivateParser(s *Server, c *call) (interface{}, *resources.Status) {
+	parser, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	parser["state"] = "INACTIVE"
+	return object{}, nil
+}
+
+// Creates a parser extension with a generated ID. The extension is validated
+// immediately and gets a passing validation report.
+func createParserExtension(s *Server, c *call) (interface{}, *resources.Status) {
+	obj, status := decodeBody(c)
+	if status != nil {
+		return nil, status
+	}
+	extensions := 0
+	for _, field := range []string{"cbnSnippet", "dynamicParsing", "fieldExtractors"} {
+		if _, ok := obj[field]; ok {
+			extensions++
+		}
+	}
+	if extensions != 1 {
+		return nil, invalidArgument("parser extension must have exactly one of cbnSnippet, dynamicParsing or fieldExtractors")
+	}
+	name := c.path.Parent().Child(resources.ParserExtensionsResourceName, newID(resources.ParserExtensionsResourceName))
+	report := name.Child(resources.ValidationReportsResourceName, newID(resources.ValidationReportsResourceName))
+	obj["name"] = name.String()
+	obj["state"] = "VALIDATED"
+	obj["validationReport"] = report.String()
+	obj["stateLastChangedTime"] = now()
+	s.put(name.String(), obj)
+	s.put(report.String(), object{
+		"name":    report.String(),
+		"verdict": "PASS",
+	})
+	return obj, nil
+}
+
+// Makes a validated parser extension the live extension of its log type and
+// archives the previous live extension
+func activateParserExtension(s *Server, c *call) (interface{}, *resources.Status) {
+	extension, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	if extension["state"] != "VALIDATED" && extension["state"] != "LIVE" {
+		return nil, failedPrecondition(fmt.Sprintf("%s is %v, only validated extensions can be activated", c.path.String(), extension["state"]))
+	}
+	for _, other := range s.children(c.path.WithoutID()) {
+		if other["state"] == "LIVE" {
+			other["state"] = "ARCHIVED"
+			other["stateLastChangedTime"] = now()
+		}
+	}
+	extension["state"] = "LIVE"
+	extension["stateLastChangedTime"] = now()
+	extension["lastLiveTime"] = extension["stateLastChangedTime"]
+	return object{}, nil
+}
+
+// Creates a rule with a generated ID and its first revision
+func createRule(s *Server, c *call) (interface{}, *resources.Status) {
+	obj, status := decodeBody(c)
+	if status != nil {
+		return nil, status
+	}
+	if text, _ := obj["text"].(string); text == "" {
+		return nil, invalidArgument("rule text is required")
+	}
+	name := c.path.Parent().Child(resources.RulesResourceName, newID(resources.RulesResourceName))
+	obj["name"] = name.String()
+	obj["revisionId"] = "v_1"
+	obj["createTime"] = now()
+	obj["revisionCreateTime"] = obj["createTime"]
+	s.put(name.String(), obj)
+	return obj, nil
+}
+
+// Updates a rule and creates a new revision
+func patchRule(s *Server, c *call) (interface{}, *resources.Status) {
+	resp, status := patchResource(s, c)
+	if status != nil {
+		return nil, status
+	}
+	rule := resp.(object)
+	var revision int
+	fmt.Sscanf(fmt.Sprint(rule["revisionId"]), "v_%d", &revision)
+	rule["revisionId"] = fmt.Sprintf("v_%d", revision+1)
+	rule["revisionCreateTime"] = now()
+	return rule, nil
+}
+
+// Creates a reference list with the ID in the referenceListId query parameter
+func createReferenceList(s *Server, c *call) (interface{}, *resources.Status) {
+	obj, status := decodeBody(c)
+	if status != nil {
+		return nil, status
+	}
+	id := c.query.Get("referenceListId")
+	if id == "" {
+		return nil, invalidArgument("referenceListId is required")
+	}
+	name := c.path.Parent().Child(resources.ReferenceListsResourceName, id)
+	if _, ok := s.objects[name.String()]; ok {
+		status := NewStatus(http.StatusConflict, fmt.Sprintf("%s already exists", name.String()))
+		return nil, &status
+	}
+	obj["name"] = name.String()
+	obj["displayName"] = id
+	obj["revisionCreateTime"] = now()
+	s.put(name.String(), obj)
+	return obj, nil
+}
+
+// Cancels an operation that is not done, it finishes with a CANCELLED error
+func cancelOperation(s *Server, c *call) (interface{}, *resources.Status) {
+	op, status := s.lookup(c)
+	if status != nil {
+		return nil, status
+	}
+	if done, _ := op["done"].(bool); !done {
+		op["done"] = true
+		// operation errors use google.rpc.Code values, 1 is CANCELLED
+		op["error"] = resources.Status{Code: 1, Message: "operation cancelled", Status: "CANCELLED"}
+	}
+	return object{}, nil
+}
+
+func decodeBody(c *call) (object, *resources.Status) {
+	obj := object{}
+	if len(c.body) == 0 {
+		return obj, nil
+	}
+	if err := json.Unmarshal(c.body, &obj); err != nil {
+		return nil, invalidArgument(fmt.Sprintf("invalid request body: %v", err))
+	}
+	if obj == nil {
+		obj = object{}
+	}
+	return obj, nil
+}
+
+func invalidArgument(message string) *resources.Status {
+	status := NewStatus(http.StatusBadRequest, message)
+	return &status
+}
+
+func failedPrecondition(message string) *resources.Status {
+	return &resources.Status{
+		Code:    http.StatusBadRequest,
+		Message: message,
+		Status:  "FAILED_PRECONDITION",
+	}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
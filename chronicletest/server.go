@@ -0,0 +1,501 @@
+// Package chronicletest provides an in-process fake Chronicle API server for
+// integration tests that need no network access.
+//
+// The fake serves log types, parsers, parser extensions and their validation
+// reports, logs, rules, reference lists and long-running operations from
+// in-memory state, with List pagination and injectable errors and latency:
+//
+//	server := chronicletest.NewServer()
+//	defer server.Close()
+//	server.Add(logtypes.NewLogTypeResource("project", "us", "instance", "WINEVTLOG"))
+//	client, err := server.NewClient()
+package chronicletest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// A stored resource, decoded from its JSON representation
+type object = map[string]interface{}
+
+// A fake Chronicle API server. Its methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// resource names in the order they were added, List methods return
+	// resources in this order
+	names   []string
+	objects map[string]object
+	faults  map[string][]resources.Status
+	latency map[string]time.Duration
+	calls   map[string]int
+	parse   ParseFunc
+}
+
+// Parses one log for the fake runParser method. cbnSnippet is empty unless
+// the request has a parser extension.
+type ParseFunc func(cbn, cbnSnippet, log []byte, statedumpAllowed bool) logtypes.RunParserResult
+
+// Starts a fake server with no resources. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		objects: map[string]object{},
+		faults:  map[string][]resources.Status{},
+		latency: map[string]time.Duration{},
+		calls:   map[string]int{},
+		parse:   genericParse,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Returns the v1alpha service endpoint of the server
+func (s *Server) Endpoint() *url.URL {
+	u, _ := url.Parse(s.URL)
+	return u.JoinPath(string(chronicleapi.V1Alpha))
+}
+
+// Creates a client of the server, see chronicleapi.NewClient
+func (s *Server) NewClient(opts ...chronicleapi.ClientOption) (*chronicleapi.Client, error) {
+	return chronicleapi.NewClient(s.Client(), s.Endpoint(), opts...)
+}
+
+// Stores resources, ex. a *logtypes.LogTypeResource, replacing any resource
+// with the same name. A resource is any value whose JSON encoding is an
+// object with the full resource name in its "name" field. Resources without
+// an ID, ex. parsers.NewParserResource(..., ""), are given a generated one.
+// Log data is base64 encoded like the API's bytes fields, ex.
+// base64.StdEncoding.EncodeToString([]byte(raw)).
+func (s *Server) Add(values ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, value := range values {
+		obj, err := toObject(value)
+		if err != nil {
+			return err
+		}
+		path, err := objectPath(obj)
+		if err != nil {
+			return err
+		}
+		if data, ok := obj["data"].(string); ok && path.Collection() == resources.LogsResourceName {
+			if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+				return fmt.Errorf("log %s data is not base64 encoded: %w", path, err)
+			}
+		}
+		if !path.HasValue() {
+			path = path.Parent().Child(path.Collection(), newID(path.Collection()))
+			obj["name"] = path.String()
+		}
+		s.put(path.String(), obj)
+	}
+	return nil
+}
+
+// Decodes the stored resource with the name into v, returns false if there
+// is no such resource
+func (s *Server) Get(name string, v interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[name]
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Makes the next count calls of the API method, ex. "parsers.create", fail
+// with the status. A method of "" matches every call. A status whose code is
+// not an HTTP status code, ex. 0, fails with 500 INTERNAL.
+func (s *Server) FailNext(method string, count int, status resources.Status) {
+	if status.Code < 100 || status.Code > 599 {
+		status = NewStatus(http.StatusInternalServerError, status.Message)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range count {
+		s.faults[method] = append(s.faults[method], status)
+	}
+}
+
+// Delays every call of the API method by the latency. A method of "" delays
+// every call. A delayed call returns early if its request is canceled.
+func (s *Server) SetLatency(method string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[method] = latency
+}
+
+// Sets the function the fake runParser method parses each log with. By
+// default every log is parsed into a GENERIC_EVENT whose description is the
+// log. The function is called with the server locked and must not call the
+// server's methods.
+func (s *Server) SetParseFunc(parse ParseFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parse = parse
+}
+
+// Returns the number of calls of the API method the server received,
+// including failed calls
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// Returns an error status with the canonical status name of the HTTP status
+// code, ex. NewStatus(404, "parser not found")
+func NewStatus(code int, message string) resources.Status {
+	status, ok := statusNames[code]
+	if !ok {
+		status = "UNKNOWN"
+	}
+	return resources.Status{
+		Code:    code,
+		Message: message,
+		Status:  status,
+	}
+}
+
+var statusNames = map[int]string{
+	http.StatusBadRequest:          "INVALID_ARGUMENT",
+	http.StatusUnauthorized:        "UNAUTHENTICATED",
+	http.StatusForbidden:           "PERMISSION_DENIED",
+	http.StatusNotFound:            "NOT_FOUND",
+	http.StatusConflict:            "ALREADY_EXISTS",
+	http.StatusTooManyRequests:     "RESOURCE_EXHAUSTED",
+	499:                            "CANCELLED",
+	http.StatusInternalServerError: "INTERNAL",
+	http.StatusNotImplemented:      "UNIMPLEMENTED",
+	http.StatusServiceUnavailable:  "UNAVAILABLE",
+	http.StatusGatewayTimeout:      "DEADLINE_EXCEEDED",
+}
+
+// A parsed API call
+type call struct {
+	// The API method name, ex. "parsers.activate"
+	method string
+	// The resource, or collection for List and create methods
+	path  resources.ResourcePath
+	verb  string
+	query url.Values
+	body  []byte
+}
+
+// Handles a call with the server lock held, returning the response body or
+// an error status
+type handlerFunc func(s *Server, c *call) (interface{}, *resources.Status)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := parseCall(r)
+	if err != nil {
+		writeError(w, NewStatus(http.StatusNotFound, err.Error()))
+		return
+	}
+	if err := s.delay(r.Context(), c.method); err != nil {
+		writeError(w, NewStatus(499, err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[c.method]++
+	if status, ok := s.fault(c.method); ok {
+		writeError(w, status)
+		return
+	}
+	handler, ok := handlers[c.method]
+	if !ok {
+		writeError(w, NewStatus(http.StatusNotFound, fmt.Sprintf("method %s not found", c.method)))
+		return
+	}
+	resp, status := handler(s, c)
+	if status != nil {
+		writeError(w, *status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Parses the API method and resource of a request URL, ex.
+// POST /v1alpha/projects/.../parsers/{parser}:activate is "parsers.activate"
+func parseCall(r *http.Request) (*call, error) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	version, name, _ := strings.Cut(name, "/")
+	if !slices.Contains(chronicleapi.APIVersions, chronicleapi.APIVersion(version)) {
+		return nil, fmt.Errorf("unknown api version %q", version)
+	}
+	name, verb, _ := strings.Cut(name, ":")
+	path, err := resources.ParseResourcePath(name)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	c := &call{
+		path:  path,
+		verb:  verb,
+		query: r.URL.Query(),
+		body:  body,
+	}
+	switch {
+	case verb != "":
+		c.method = verb
+	case r.Method == http.MethodGet && path.HasValue():
+		c.method = "get"
+	case r.Method == http.MethodGet:
+		c.method = "list"
+	case r.Method == http.MethodPost && !path.HasValue():
+		c.method = "create"
+	case r.Method == http.MethodPatch:
+		c.method = "patch"
+	case r.Method == http.MethodDelete:
+		c.method = "delete"
+	default:
+		return nil, fmt.Errorf("unsupported %s %s", r.Method, r.URL.Path)
+	}
+	c.method = path.Collection() + "." + c.method
+	return c, nil
+}
+
+func (s *Server) delay(ctx context.Context, method string) error {
+	s.mu.Lock()
+	latency := s.latency[""] + s.latency[method]
+	s.mu.Unlock()
+	if latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pops the next injected fault for the method
+func (s *Server) fault(method string) (resources.Status, bool) {
+	for _, key := range []string{method, ""} {
+		if faults := s.faults[key]; len(faults) > 0 {
+			s.faults[key] = faults[1:]
+			return faults[0], true
+		}
+	}
+	return resources.Status{}, false
+}
+
+func writeError(w http.ResponseWriter, status resources.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status.Code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"error": status})
+}
+
+func (s *Server) put(name string, obj object) {
+	if _, ok := s.objects[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.objects[name] = obj
+}
+
+func (s *Server) remove(name string) {
+	delete(s.objects, name)
+	s.names = slices.DeleteFunc(s.names, func(n string) bool { return n == name })
+}
+
+// Returns the resource with the name of the call path, or a NOT_FOUND status
+func (s *Server) lookup(c *call) (object, *resources.Status) {
+	obj, ok := s.objects[c.path.String()]
+	if !ok {
+		status := NewStatus(http.StatusNotFound, fmt.Sprintf("%s not found", c.path.String()))
+		return nil, &status
+	}
+	return obj, nil
+}
+
+// Returns the resources of a collection path in order. IDs in the path may
+// be the "-" wildcard.
+func (s *Server) children(collection resources.ResourcePath) []object {
+	pattern := strings.Split(collection.WithoutID().String(), "/")
+	var children []object
+	for _, name := range s.names {
+		path, err := resources.ParseResourcePath(name)
+		if err != nil {
+			continue
+		}
+		elements := strings.Split(path.WithoutID().String(), "/")
+		if slices.EqualFunc(pattern, elements, func(p, e string) bool { return p == e || p == resources.WildcardID }) {
+			children = append(children, s.objects[name])
+		}
+	}
+	return children
+}
+
+// Decodes the JSON encoding of a resource as an object
+func toObject(v interface{}) (object, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	obj := object{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("resource is not a JSON object: %w", err)
+	}
+	return obj, nil
+}
+
+func objectPath(obj object) (resources.ResourcePath, error) {
+	name, _ := obj["name"].(string)
+	path, err := resources.ParseResourcePath(name)
+	if err != nil {
+		return resources.ResourcePath{}, fmt.Errorf("resource has an invalid name: %w", err)
+	}
+	return path, nil
+}
+
+// Generates a resource ID in the format the API uses for the collection
+func newID(collection string) string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	uuid := fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	switch collection {
+	case resources.RulesResourceName:
+		return "ru_" + uuid
+	case resources.RetrohuntsResourceName:
+		return "oh_" + uuid
+	}
+	return uuid
+}
+
+// Returns the page of items selected by the pageSize and pageToken query
+// parameters and the token of the next page. Page tokens encode the offset
+// of the page and the query they belong to.
+func paginate(c *call, items []object) ([]object, string, *resources.Status) {
+	pageSize := DefaultPageSize
+	if size := c.query.Get("pageSize"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 0 {
+			status := NewStatus(http.StatusBadRequest, fmt.Sprintf("invalid page size %q", size))
+			return nil, "", &status
+		}
+		if n > 0 {
+			pageSize = min(n, MaxPageSize)
+		}
+	}
+	offset := 0
+	if token := c.query.Get("pageToken"); token != "" {
+		n, ok := decodePageToken(c, token)
+		if !ok {
+			status := NewStatus(http.StatusBadRequest, fmt.Sprintf("invalid page token %q", token))
+			return nil, "", &status
+		}
+		offset = n
+	}
+	if offset >= len(items) {
+		return nil, "", nil
+	}
+	end := min(offset+pageSize, len(items))
+	nextPageToken := ""
+	if end < len(items) {
+		nextPageToken = encodePageToken(c, end)
+	}
+	return items[offset:end], nextPageToken, nil
+}
+
+func encodePageToken(c *call, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d|%s|%s", offset, c.path.String(), c.query.Get("filter"))))
+}
+
+func decodePageToken(c *call, token string) (int, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, false
+	}
+	offset, query, ok := strings.Cut(string(data), "|")
+	if !ok || query != c.path.String()+"|"+c.query.Get("filter") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(offset)
+	return n, err == nil && n >= 0
+}
+
+// Returns the items matching a filter of `field = value` comparisons joined
+// by AND, ex. `state = "ACTIVE" AND type = "CUSTOM"`. Fields are top-level
+// resource fields, string values are quoted.
+func filter(c *call, items []object) ([]object, *resources.Status) {
+	expr := strings.TrimSpace(c.query.Get("filter"))
+	if expr == "" {
+		return items, nil
+	}
+	type comparison struct {
+		field string
+		value interface{}
+	}
+	var comparisons []comparison
+	for _, clause := range strings.Split(expr, " AND ") {
+		field, value, ok := strings.Cut(clause, "=")
+		var v interface{}
+		if !ok || json.Unmarshal([]byte(strings.TrimSpace(value)), &v) != nil {
+			status := NewStatus(http.StatusBadRequest, fmt.Sprintf("invalid filter %q", expr))
+			return nil, &status
+		}
+		comparisons = append(comparisons, comparison{strings.TrimSpace(field), v})
+	}
+	var matches []object
+	for _, item := range items {
+		if !slices.ContainsFunc(comparisons, func(cmp comparison) bool { return !equalValue(item[cmp.field], cmp.value) }) {
+			matches = append(matches, item)
+		}
+	}
+	return matches, nil
+}
+
+// Compares a resource field to a filter value, a missing field matches the
+// zero value
+func equalValue(field, value interface{}) bool {
+	if field == nil {
+		switch value := value.(type) {
+		case string:
+			return value == ""
+		case bool:
+			return !value
+		case float64:
+			return value == 0
+		}
+	}
+	return field == value
+}
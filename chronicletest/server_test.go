@@ -0,0 +1,235 @@
+package chronicletest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logs"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/operations"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const instancePath = "projects/testproject/locations/us/instances/testinstance"
+
+func newServer(t *testing.T, opts ...chronicleapi.ClientOption) (*chronicletest.Server, *chronicleapi.Client) {
+	server := chronicletest.NewServer()
+	t.Cleanup(server.Close)
+	client, err := server.NewClient(opts...)
+	require.NoError(t, err)
+	return server, client
+}
+
+func TestServerLogTypes(t *testing.T) {
+	server, client := newServer(t)
+	for i := range 5 {
+		require.NoError(t, server.Add(logtypes.NewLogTypeResource("testproject", "us", "testinstance", fmt.Sprintf("LOGTYPE_%d", i))))
+	}
+	ctx := context.Background()
+
+	logtype, err := client.LogTypes().Get(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "LOGTYPE_3"))
+	require.NoError(t, err)
+	assert.Equal(t, instancePath+"/logTypes/LOGTYPE_3", logtype.Name.String())
+
+	_, err = client.LogTypes().Get(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "MISSING"))
+	assert.True(t, chronicleapi.IsNotFound(err))
+
+	page, err := client.LogTypes().List(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", ""), "2", "")
+	require.NoError(t, err)
+	assert.Len(t, page.LogTypes, 2)
+	assert.NotEmpty(t, page.NextPageToken)
+
+	all, err := client.LogTypes().Pager(logtypes.NewLogTypeResource("testproject", "us", "testinstance", ""), "2").Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	assert.Equal(t, instancePath+"/logTypes/LOGTYPE_4", all[4].Name.String())
+	assert.Equal(t, 4, server.Calls("logTypes.list"))
+
+	_, err = client.LogTypes().List(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", ""), "2", "bogus")
+	assert.True(t, chronicleapi.IsInvalidArgument(err))
+}
+
+func TestServerParsers(t *testing.T) {
+	server, client := newServer(t)
+	ctx := context.Background()
+
+	first := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "")
+	first.Cbn = []byte("first parser")
+	created, err := client.Parsers().Create(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, created.Name.HasValue())
+	assert.Equal(t, "INACTIVE", created.State)
+	assert.Equal(t, []byte("first parser"), created.Cbn)
+	require.NoError(t, client.Parsers().Activate(ctx, created))
+
+	var parser parsers.ParserResource
+	require.True(t, server.Get(created.Name.String(), &parser))
+	assert.Equal(t, "ACTIVE", parser.State)
+
+	_, err = client.Parsers().Create(ctx, parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", ""))
+	assert.True(t, chronicleapi.IsInvalidArgument(err))
+	assert.True(t, chronicleapi.IsNotFound(client.Parsers().Activate(ctx, parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "missing"))))
+}
+
+func TestServerActivateParser(t *testing.T) {
+	server, client := newServer(t)
+	ctx := context.Background()
+	first := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "first")
+	first.State = "ACTIVE"
+	second := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "second")
+	require.NoError(t, server.Add(first, second))
+
+	require.NoError(t, client.Parsers().Activate(ctx, second))
+	var parser parsers.ParserResource
+	require.True(t, server.Get(first.Name.String(), &parser))
+	assert.Equal(t, "INACTIVE", parser.State)
+	require.True(t, server.Get(second.Name.String(), &parser))
+	assert.Equal(t, "ACTIVE", parser.State)
+
+	require.NoError(t, client.Parsers().Deactivate(ctx, second))
+	require.True(t, server.Get(second.Name.String(), &parser))
+	assert.Equal(t, "INACTIVE", parser.State)
+}
+
+func TestServerLogs(t *testing.T) {
+	server, client := newServer(t)
+	for i := range 3 {
+		log := logs.NewLogResource("testproject", "us", "testinstance", "WINEVTLOG", fmt.Sprintf("log%d", i))
+		log.Data = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("log %d", i)))
+		log.EnvironmentNamespace = "prod"
+		if i == 1 {
+			log.EnvironmentNamespace = "dev"
+		}
+		require.NoError(t, server.Add(log))
+	}
+	require.NoError(t, server.Add(logs.NewLogResource("testproject", "us", "testinstance", "PAN_FIREWALL", "log")))
+	ctx := context.Background()
+
+	all, err := client.Logs().Pager(logs.NewLogResource("testproject", "us", "testinstance", "WINEVTLOG", ""), "1", "").Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("log 2")), all[2].Data)
+
+	prod, err := client.Logs().List(ctx, logs.NewLogResource("testproject", "us", "testinstance", "WINEVTLOG", ""), "", "", `environmentNamespace = "prod"`)
+	require.NoError(t, err)
+	assert.Len(t, prod.Logs, 2)
+
+	wildcard, err := client.Logs().List(ctx, logs.NewLogResource("testproject", "us", "testinstance", "-", ""), "", "", "")
+	require.NoError(t, err)
+	assert.Len(t, wildcard.Logs, 4)
+}
+
+func TestServerRulesAndReferenceLists(t *testing.T) {
+	server, _ := newServer(t)
+	post := func(path, body string) (*http.Response, string) {
+		resp, err := server.Client().Post(server.Endpoint().String()+"/"+path, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp, string(respBody)
+	}
+
+	resp, body := post(instancePath+"/rules", `{"text": "rule test {}"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"name":"`+instancePath+`/rules/ru_`)
+	assert.Contains(t, body, `"revisionId":"v_1"`)
+	resp, _ = post(instancePath+"/rules", `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body = post(instancePath+"/referenceLists?referenceListId=hosts", `{"entries": [{"value": "host1"}]}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"name":"`+instancePath+`/referenceLists/hosts"`)
+	resp, body = post(instancePath+"/referenceLists?referenceListId=hosts", `{}`)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Contains(t, body, "ALREADY_EXISTS")
+
+	req, _ := http.NewRequest(http.MethodPatch, server.Endpoint().String()+"/"+instancePath+"/referenceLists/hosts?updateMask=entries", strings.NewReader(`{"entries": [{"value": "host2"}], "description": "ignored"}`))
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var list map[string]interface{}
+	require.True(t, server.Get(instancePath+"/referenceLists/hosts", &list))
+	assert.Equal(t, []interface{}{map[string]interface{}{"value": "host2"}}, list["entries"])
+	assert.NotContains(t, list, "description")
+}
+
+func TestServerOperations(t *testing.T) {
+	server, client := newServer(t, chronicleapi.WithPollInterval(time.Millisecond, time.Millisecond))
+	op := operations.NewOperationResource("testproject", "us", "testinstance", "op1")
+	require.NoError(t, server.Add(op, operations.NewOperationResource("testproject", "us", "testinstance", "op2")))
+	ctx := context.Background()
+
+	require.NoError(t, client.Operations().Cancel(ctx, op))
+	_, err := client.Operations().Wait(ctx, op, nil)
+	var apiErr *chronicleapi.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "CANCELLED", apiErr.Status.Status)
+
+	done, err := client.Operations().List(ctx, operations.NewOperationResource("testproject", "us", "testinstance", ""), "", "", "done = true")
+	require.NoError(t, err)
+	require.Len(t, done.Operations, 1)
+	assert.Equal(t, op.Name, done.Operations[0].Name)
+
+	require.NoError(t, client.Operations().Delete(ctx, op))
+	assert.False(t, server.Get(op.Name.String(), &operations.OperationResource{}))
+}
+
+func TestServerFaults(t *testing.T) {
+	server, client := newServer(t)
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	require.NoError(t, server.Add(logtype))
+	ctx := context.Background()
+
+	server.FailNext("logTypes.get", 2, chronicletest.NewStatus(http.StatusServiceUnavailable, "try again"))
+	for range 2 {
+		_, err := client.LogTypes().Get(ctx, logtype)
+		assert.True(t, chronicleapi.IsUnavailable(err))
+	}
+	_, err := client.LogTypes().Get(ctx, logtype)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, server.Calls("logTypes.get"))
+
+	server.SetLatency("logTypes.get", time.Second)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.LogTypes().Get(timeoutCtx, logtype)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestServerRunParser(t *testing.T) {
+	_, client := newServer(t)
+	resp, err := client.LogTypes().RunParser(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"), []byte("filter {}"), nil, [][]byte{[]byte("log 1"), []byte("log 2")}, true)
+	require.NoError(t, err)
+	require.Len(t, resp.RunParserResults, 2)
+	event, ok := resp.RunParserResults[1].Event("GENERIC_EVENT")
+	require.True(t, ok)
+	description, _ := event.Get("metadata.description")
+	assert.Equal(t, "log 2", description)
+	assert.Equal(t, `{"message":"log 2"}`, resp.RunParserResults[1].StatedumpResults[0].StatedumpResult)
+}
+
+func TestServerFaultWithoutCode(t *testing.T) {
+	server, client := newServer(t)
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	require.NoError(t, server.Add(logtype))
+
+	server.FailNext("logTypes.get", 1, resources.Status{Message: "no code"})
+	_, err := client.LogTypes().Get(context.Background(), logtype)
+	var apiErr *chronicleapi.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.HTTPStatusCode)
+	assert.Equal(t, "INTERNAL", apiErr.Status.Status)
+	assert.Equal(t, "no code", apiErr.Message)
+}
@@ -0,0 +1,185 @@
+package chronicleapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/calebryant/chronicle-api/resources"
+)
+
+// A Chronicle API client that sends the requests built by the resources
+// packages and decodes the responses into resource structs.
+//
+// The http client is expected to handle authentication, ex. the client
+// returned by auth.NewClient, or an auth.Router client to use different
+// credentials for each instance.
+type Client struct {
+	httpClient      *http.Client
+	serviceEndpoint *url.URL
+	// The service endpoint without its API version path
+	baseEndpoint *url.URL
+	// API versions the client may call, in order of preference
+	versions []APIVersion
+	// Middleware in the order it was added, the first is outermost
+	middleware []Middleware
+	// The middleware chain around the http client
+	handler Handler
+	// Operation polling backoff used by OperationsService.Wait
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+}
+
+// Configures optional Client behavior
+type ClientOption func(*Client)
+
+// Creates a new Chronicle API client
+//
+// If the service endpoint ends with an API version, ex. the endpoints built
+// by NewServiceEndpoint, the client only calls that version unless
+// WithAPIVersions says otherwise.
+func NewClient(httpClient *http.Client, serviceEndpoint *url.URL, opts ...ClientOption) (*Client, error) {
+	if httpClient == nil {
+		return nil, fmt.Errorf("missing http client")
+	}
+	if serviceEndpoint == nil {
+		return nil, fmt.Errorf("missing service endpoint")
+	}
+	c := &Client{
+		httpClient:      httpClient,
+		serviceEndpoint: serviceEndpoint,
+		pollInterval:    defaultPollInterval,
+		maxPollInterval: defaultMaxPollInterval,
+	}
+	baseEndpoint, versions, err := splitVersion(serviceEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	c.baseEndpoint, c.versions = baseEndpoint, versions
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.versions) == 0 {
+		return nil, fmt.Errorf("no api versions")
+	}
+	c.handler = chain(c.send, c.middleware)
+	return c, nil
+}
+
+// Adds middleware to the end of the client's middleware chain
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// Makes every call wait on the rate limiter before it is sent, see
+// RateLimitMiddleware
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return WithMiddleware(RateLimitMiddleware(limiter))
+}
+
+// Sets the initial and maximum wait between polls of a long-running
+// operation. Non-positive intervals keep the defaults and the maximum is at
+// least the initial interval.
+func WithPollInterval(initial, maximum time.Duration) ClientOption {
+	return func(c *Client) {
+		if initial > 0 {
+			c.pollInterval = initial
+		}
+		if maximum > 0 {
+			c.maxPollInterval = maximum
+		}
+		c.maxPollInterval = max(c.maxPollInterval, c.pollInterval)
+	}
+}
+
+// Sets the API versions the client may call, in order of preference. Each
+// call uses the first of these versions that supports its method.
+func WithAPIVersions(versions ...APIVersion) ClientOption {
+	return func(c *Client) {
+		c.versions = versions
+	}
+}
+
+// Returns the service endpoint the client was created with
+func (c *Client) ServiceEndpoint() *url.URL {
+	return c.serviceEndpoint
+}
+
+func (c *Client) Instances() *InstancesService {
+	return &InstancesService{client: c}
+}
+
+func (c *Client) LogTypes() *LogTypesService {
+	return &LogTypesService{client: c}
+}
+
+func (c *Client) Logs() *LogsService {
+	return &LogsService{client: c}
+}
+
+func (c *Client) Operations() *OperationsService {
+	return &OperationsService{client: c}
+}
+
+func (c *Client) ParserExtensions() *ParserExtensionsService {
+	return &ParserExtensionsService{client: c}
+}
+
+func (c *Client) Parsers() *ParsersService {
+	return &ParsersService{client: c}
+}
+
+func (c *Client) ValidationReports() *ValidationReportsService {
+	return &ValidationReportsService{client: c}
+}
+
+// Builds a request with one of the resources package builders, sends it and
+// decodes the JSON response body into v. A nil v discards the response body.
+//
+// method is the API method, ex. logTypesRunParser, and path is the resource
+// the method targets. The request is built against the service endpoint of
+// the best API version that supports the method.
+func (c *Client) do(ctx context.Context, method *apiMethod, path resources.ResourcePath, build func(serviceEndpoint *url.URL) (*http.Request, error), v interface{}) error {
+	if err := ValidateLocation(c.serviceEndpoint, path); err != nil {
+		return err
+	}
+	version, err := c.version(method)
+	if err != nil {
+		return err
+	}
+	req, err := build(c.baseEndpoint.JoinPath(string(version)))
+	if err != nil {
+		return err
+	}
+	resp, err := c.handler(&Call{
+		Method:  method.name,
+		Path:    path,
+		Request: req.WithContext(ctx),
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp, body)
+	}
+	if v == nil || len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Sends a call with the http client, the end of the middleware chain
+func (c *Client) send(call *Call) (*http.Response, error) {
+	return c.httpClient.Do(call.Request)
+}
@@ -0,0 +1,270 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/parserextensions"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+	"github.com/calebryant/chronicle-api/resources/validationreports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...chronicleapi.ClientOption) *chronicleapi.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	u, _ := url.Parse(server.URL)
+	client, err := chronicleapi.NewClient(server.Client(), u.JoinPath("v1alpha"), opts...)
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	u, _ := url.Parse("https://test.local")
+	_, err := chronicleapi.NewClient(nil, u)
+	assert.Error(t, err)
+	_, err = chronicleapi.NewClient(http.DefaultClient, nil)
+	assert.Error(t, err)
+	client, err := chronicleapi.NewClient(http.DefaultClient, u)
+	require.NoError(t, err)
+	assert.Equal(t, u, client.ServiceEndpoint())
+}
+
+func TestClientLogTypes(t *testing.T) {
+	testproject := "testproject"
+	testlocation := "us"
+	testinstance := "testinstance"
+	testlogtype := "WINEVTLOG"
+	logtypePath := fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes", testproject, testlocation, testinstance)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1alpha/" + logtypePath + "/" + testlogtype:
+			fmt.Fprintf(w, `{"name": "%s/%s", "displayName": "Windows Event Log", "golden": true}`, logtypePath, testlogtype)
+		case "/v1alpha/" + logtypePath:
+			assert.Equal(t, "abcdefg", r.URL.Query().Get("pageToken"))
+			fmt.Fprintf(w, `{"logTypes": [{"name": "%s/%s"}, {"name": "%s/PAN_FIREWALL"}], "nextPageToken": "hijklmn"}`, logtypePath, testlogtype, logtypePath)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": 404, "message": "not found", "status": "NOT_FOUND"}}`)
+		}
+	})
+	ctx := context.Background()
+
+	logtype, err := client.LogTypes().Get(ctx, logtypes.NewLogTypeResource(testproject, testlocation, testinstance, testlogtype))
+	require.NoError(t, err)
+	assert.Equal(t, logtypePath+"/"+testlogtype, logtype.Name.String())
+	assert.Equal(t, "Windows Event Log", logtype.DisplayName)
+	assert.True(t, logtype.Golden)
+
+	list, err := client.LogTypes().List(ctx, logtypes.NewLogTypeResource(testproject, testlocation, testinstance, ""), "2", "abcdefg")
+	require.NoError(t, err)
+	require.Len(t, list.LogTypes, 2)
+	assert.Equal(t, logtypePath+"/PAN_FIREWALL", list.LogTypes[1].Name.String())
+	assert.Equal(t, "hijklmn", list.NextPageToken)
+
+	_, err = client.LogTypes().Get(ctx, logtypes.NewLogTypeResource(testproject, testlocation, testinstance, "MISSING"))
+	assert.Error(t, err)
+
+	_, err = client.LogTypes().Get(ctx, nil)
+	assert.Error(t, err)
+}
+
+func TestClientParsers(t *testing.T) {
+	testproject := "testproject"
+	testlocation := "us"
+	testinstance := "testinstance"
+	testlogtype := "WINEVTLOG"
+	parserPath := fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/%s/parsers", testproject, testlocation, testinstance, testlogtype)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1alpha/"+parserPath:
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			fmt.Fprintf(w, `{"name": "%s/12345", "state": "ACTIVE", "cbn": "dGVzdCBjYm4gcGFyc2Vy"}`, parserPath)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1alpha/"+parserPath+"/12345:activate":
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	ctx := context.Background()
+
+	parser := parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, "")
+	parser.Cbn = []byte("test cbn parser")
+	created, err := client.Parsers().Create(ctx, parser)
+	require.NoError(t, err)
+	assert.Equal(t, parserPath+"/12345", created.Name.String())
+	assert.Equal(t, "ACTIVE", created.State)
+	assert.Equal(t, []byte("test cbn parser"), created.Cbn)
+
+	assert.NoError(t, client.Parsers().Activate(ctx, created))
+	assert.Error(t, client.Parsers().Deactivate(ctx, created))
+}
+
+func TestClientParserLifecycle(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	prebuilt := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "prebuilt")
+	prebuilt.Type = parsers.TypePrebuilt
+	prebuilt.State = parsers.StateActive
+	prebuilt.Cbn = []byte("prebuilt cbn")
+	other := parsers.NewParserResource("testproject", "us", "testinstance", "PAN_FIREWALL", "other")
+	other.Type = parsers.TypeCustom
+	require.NoError(t, server.Add(prebuilt, other))
+	ctx := context.Background()
+
+	parser, err := client.Parsers().Get(ctx, prebuilt)
+	require.NoError(t, err)
+	assert.Equal(t, prebuilt.Name, parser.Name)
+	assert.Equal(t, []byte("prebuilt cbn"), parser.Cbn)
+
+	copied, err := client.Parsers().Copy(ctx, prebuilt)
+	require.NoError(t, err)
+	assert.NotEqual(t, prebuilt.Name, copied.Name)
+	assert.Equal(t, prebuilt.Name.Parent(), copied.Name.Parent())
+	assert.Equal(t, parsers.TypeCustom, copied.Type)
+	assert.Equal(t, []byte("prebuilt cbn"), copied.Cbn)
+
+	all, err := client.Parsers().Pager(parsers.NewParserResource("testproject", "us", "testinstance", "-", ""), "1", "").Collect(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+	custom, err := client.Parsers().List(ctx, parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", ""), "", "", parsers.Filter("", parsers.TypeCustom))
+	require.NoError(t, err)
+	require.Len(t, custom.Parsers, 1)
+	assert.Equal(t, copied.Name, custom.Parsers[0].Name)
+
+	require.NoError(t, client.Parsers().Delete(ctx, copied, false))
+	_, err = client.Parsers().Get(ctx, copied)
+	assert.True(t, chronicleapi.IsNotFound(err))
+	assert.Error(t, client.Parsers().Delete(ctx, prebuilt, false))
+	require.NoError(t, client.Parsers().Delete(ctx, prebuilt, true))
+	assert.Error(t, client.Parsers().Delete(ctx, nil, true))
+}
+
+func TestClientValidationReports(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	report := validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "abc")
+	report.Verdict = validationreports.VerdictFail
+	report.Stats = &validationreports.ValidationStats{LogEntryCount: 3, FailedLogCount: 2}
+	parser := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "12345")
+	parser.ValidationReport = report.Name
+	parser.Changelogs = &parsers.Changelogs{Entries: []parsers.ChangelogEntry{{CreateTime: "2024-01-01T00:00:00Z", ChangeMessage: "initial version"}}}
+	require.NoError(t, server.Add(parser, report))
+	for i, log := range []string{"bad log 1", "bad log 2"} {
+		require.NoError(t, server.Add(&validationreports.ParsingErrorResource{
+			Name:    report.Name.Child(resources.ParsingErrorsResourceName, fmt.Sprint(i)),
+			LogData: []byte(log),
+			Error:   &resources.Status{Code: 3, Message: "no match"},
+		}))
+	}
+	ctx := context.Background()
+
+	got, err := client.Parsers().Get(ctx, parser)
+	require.NoError(t, err)
+	require.NotNil(t, got.Changelogs)
+	assert.Equal(t, "initial version", got.Changelogs.Entries[0].ChangeMessage)
+
+	gotReport, err := client.ValidationReports().Get(ctx, &validationreports.ValidationReportResource{Name: got.ValidationReport})
+	require.NoError(t, err)
+	assert.False(t, gotReport.Passed())
+	assert.Equal(t, int64(2), gotReport.Stats.FailedLogCount)
+
+	parsingErrors, err := client.ValidationReports().ParsingErrorsPager(gotReport, "1", "").Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, parsingErrors, 2)
+	assert.Equal(t, []byte("bad log 2"), parsingErrors[1].LogData)
+	assert.Equal(t, "no match", parsingErrors[1].Error.Message)
+}
+
+func TestClientParserExtensions(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	snippet := parserextensions.NewParserExtensionResource("testproject", "us", "testinstance", "WINEVTLOG", "")
+	snippet.CbnSnippet = []byte("filter {}")
+	snippet.Log = []byte("sample log")
+	first, err := client.ParserExtensions().Create(ctx, snippet)
+	require.NoError(t, err)
+	assert.True(t, first.Name.HasValue())
+	assert.Equal(t, parserextensions.StateValidated, first.State)
+	assert.Equal(t, []byte("filter {}"), first.CbnSnippet)
+	require.NoError(t, client.ParserExtensions().Activate(ctx, first))
+
+	mapping := parserextensions.NewParserExtensionResource("testproject", "us", "testinstance", "WINEVTLOG", "")
+	mapping.DynamicParsing = &parserextensions.DynamicParsing{OptedFields: []parserextensions.OptedField{{Path: "user.name", SampleValue: "alice"}}}
+	second, err := client.ParserExtensions().Create(ctx, mapping)
+	require.NoError(t, err)
+	assert.Equal(t, "user.name", second.DynamicParsing.OptedFields[0].Path)
+	require.NoError(t, client.ParserExtensions().Activate(ctx, second))
+
+	extensions, err := client.ParserExtensions().Pager(parserextensions.NewParserExtensionResource("testproject", "us", "testinstance", "-", ""), "1", "").Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, extensions, 2)
+	assert.Equal(t, parserextensions.StateArchived, extensions[0].State)
+	assert.Equal(t, parserextensions.StateLive, extensions[1].State)
+
+	report, err := client.ParserExtensions().ValidationReport(ctx, parserextensions.NewParserExtensionResource("testproject", "us", "testinstance", "WINEVTLOG", second.Name.ID()))
+	require.NoError(t, err)
+	assert.True(t, report.Passed())
+	assert.Equal(t, second.ValidationReport, report.Name)
+
+	require.NoError(t, client.ParserExtensions().Delete(ctx, first))
+	_, err = client.ParserExtensions().Get(ctx, first)
+	assert.True(t, chronicleapi.IsNotFound(err))
+	_, err = client.ParserExtensions().Create(ctx, parserextensions.NewParserExtensionResource("testproject", "us", "testinstance", "WINEVTLOG", ""))
+	assert.Error(t, err)
+}
+
+func TestClientRunParser(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	server.SetParseFunc(func(cbn, cbnSnippet, log []byte, statedumpAllowed bool) logtypes.RunParserResult {
+		if string(log) == "bad" {
+			return logtypes.RunParserResult{Error: &resources.Status{Code: 3, Message: "no match"}}
+		}
+		event := logtypes.UDM{
+			"metadata":  map[string]interface{}{"eventType": "NETWORK_CONNECTION"},
+			"principal": map[string]interface{}{"ip": []interface{}{string(log)}},
+		}
+		return logtypes.RunParserResult{ParsedEvents: &logtypes.ParsedEvents{Events: []logtypes.EventOrEntity{{Event: event}}}}
+	})
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	logs := [][]byte{[]byte("10.0.0.1"), []byte("bad"), []byte("10.0.0.3")}
+	ctx := context.Background()
+
+	resp, err := client.LogTypes().RunParser(ctx, logtype, []byte("filter {}"), nil, logs, false)
+	require.NoError(t, err)
+	require.Len(t, resp.RunParserResults, 3)
+	result := resp.RunParserResults[2]
+	assert.Equal(t, []byte("10.0.0.3"), result.Log)
+	event, ok := result.Event("NETWORK_CONNECTION")
+	require.True(t, ok)
+	ip, _ := event.Get("principal.ip")
+	assert.Equal(t, []interface{}{"10.0.0.3"}, ip)
+	failed := resp.Errors()
+	require.Len(t, failed, 1)
+	assert.Equal(t, 1, failed[0].Index)
+
+	_, err = client.LogTypes().RunParser(ctx, logtype, nil, nil, logs, false)
+	assert.Error(t, err)
+	_, err = client.LogTypes().RunParser(ctx, nil, []byte("filter {}"), nil, logs, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package chronicleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/calebryant/chronicle-api/resources"
+)
+
+// An error returned by the Chronicle API, decoded from a google.rpc.Status
+// response body
+//
+// https://cloud.google.com/apis/design/errors#http_mapping
+type APIError struct {
+	resources.Status
+	// The HTTP status code of the response, 0 if the error did not come from an HTTP response (ex. a failed long-running operation)
+	HTTPStatusCode int
+	Header         http.Header
+	// The raw response body
+	Body []byte
+}
+
+// Creates an APIError from a non-2xx response and its body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		HTTPStatusCode: resp.StatusCode,
+		Header:         resp.Header,
+		Body:           body,
+	}
+	errorBody := struct {
+		Error *resources.Status `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, &errorBody); err == nil && errorBody.Error != nil {
+		apiErr.Status = *errorBody.Error
+	}
+	if apiErr.Code == 0 {
+		apiErr.Code = resp.StatusCode
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = string(body)
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	code := e.Status.Status
+	if code == "" {
+		code = strconv.Itoa(e.Code)
+	}
+	return fmt.Sprintf("chronicle api error %s: %s", code, e.Message)
+}
+
+// Returns the field violations from a BadRequest error detail
+func (e *APIError) FieldViolations() []resources.FieldViolation {
+	badRequest := e.BadRequest()
+	if badRequest == nil {
+		return nil
+	}
+	return badRequest.FieldViolations
+}
+
+// Returns how long to wait before retrying, taken from the RetryInfo error
+// detail or the Retry-After header. Returns false if the server gave no hint.
+func (e *APIError) RetryAfter() (time.Duration, bool) {
+	if delay, ok := e.RetryInfo().Delay(); ok {
+		return delay, true
+	}
+	retryAfter := e.Header.Get("Retry-After")
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// Reports whether err is an APIError with the given canonical status, or
+// with the matching HTTP status code when the body had no status
+func hasStatus(err error, status string, httpStatusCode int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Status.Status != "" {
+		return apiErr.Status.Status == status
+	}
+	return apiErr.HTTPStatusCode == httpStatusCode
+}
+
+func IsInvalidArgument(err error) bool {
+	return hasStatus(err, "INVALID_ARGUMENT", http.StatusBadRequest)
+}
+
+func IsPermissionDenied(err error) bool {
+	return hasStatus(err, "PERMISSION_DENIED", http.StatusForbidden)
+}
+
+func IsNotFound(err error) bool {
+	return hasStatus(err, "NOT_FOUND", http.StatusNotFound)
+}
+
+func IsAlreadyExists(err error) bool {
+	return hasStatus(err, "ALREADY_EXISTS", http.StatusConflict)
+}
+
+func IsResourceExhausted(err error) bool {
+	return hasStatus(err, "RESOURCE_EXHAUSTED", http.StatusTooManyRequests)
+}
+
+func IsUnavailable(err error) bool {
+	return hasStatus(err, "UNAVAILABLE", http.StatusServiceUnavailable)
+}
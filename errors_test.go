@@ -0,0 +1,109 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError(t *testing.T) {
+	tt := []struct {
+		name               string
+		httpStatus         int
+		header             map[string]string
+		body               string
+		isNotFound         bool
+		isAlreadyExists    bool
+		isPermissionDenied bool
+		isInvalidArgument  bool
+		isExhausted        bool
+		expectedStatus     string
+		expectedRetry      time.Duration
+		expectedViolations []resources.FieldViolation
+	}{
+		{
+			name:           "Not found",
+			httpStatus:     http.StatusNotFound,
+			body:           `{"error": {"code": 404, "message": "log type not found", "status": "NOT_FOUND"}}`,
+			isNotFound:     true,
+			expectedStatus: "NOT_FOUND",
+		},
+		{
+			name:            "Already exists",
+			httpStatus:      http.StatusConflict,
+			body:            `{"error": {"code": 409, "message": "parser exists", "status": "ALREADY_EXISTS"}}`,
+			isAlreadyExists: true,
+			expectedStatus:  "ALREADY_EXISTS",
+		},
+		{
+			name:       "Permission denied",
+			httpStatus: http.StatusForbidden,
+			body: `{"error": {"code": 403, "message": "denied", "status": "PERMISSION_DENIED", "details": [
+				{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "IAM_PERMISSION_DENIED", "domain": "chronicle.googleapis.com"}
+			]}}`,
+			isPermissionDenied: true,
+			expectedStatus:     "PERMISSION_DENIED",
+		},
+		{
+			name:       "Bad request with field violations",
+			httpStatus: http.StatusBadRequest,
+			body: `{"error": {"code": 400, "message": "invalid", "status": "INVALID_ARGUMENT", "details": [
+				{"@type": "type.googleapis.com/google.rpc.BadRequest", "fieldViolations": [{"field": "parser.cbn", "description": "cbn is empty"}]}
+			]}}`,
+			isInvalidArgument:  true,
+			expectedStatus:     "INVALID_ARGUMENT",
+			expectedViolations: []resources.FieldViolation{{Field: "parser.cbn", Description: "cbn is empty"}},
+		},
+		{
+			name:       "Quota exhausted with retry info",
+			httpStatus: http.StatusTooManyRequests,
+			body: `{"error": {"code": 429, "message": "quota", "status": "RESOURCE_EXHAUSTED", "details": [
+				{"@type": "type.googleapis.com/google.rpc.QuotaFailure", "violations": [{"subject": "runParser"}]},
+				{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "1.500s"}
+			]}}`,
+			isExhausted:    true,
+			expectedStatus: "RESOURCE_EXHAUSTED",
+			expectedRetry:  1500 * time.Millisecond,
+		},
+		{
+			name:          "Non JSON body with Retry-After header",
+			httpStatus:    http.StatusTooManyRequests,
+			header:        map[string]string{"Retry-After": "3"},
+			body:          `slow down`,
+			isExhausted:   true,
+			expectedRetry: 3 * time.Second,
+		},
+	}
+	for _, tt := range tt {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range tt.header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(tt.httpStatus)
+			fmt.Fprint(w, tt.body)
+		})
+		_, err := client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+		require.Error(t, err, tt.name)
+		var apiErr *chronicleapi.APIError
+		require.ErrorAs(t, err, &apiErr, tt.name)
+		assert.Equal(t, tt.httpStatus, apiErr.HTTPStatusCode, tt.name)
+		assert.Equal(t, tt.expectedStatus, apiErr.Status.Status, tt.name)
+		assert.Equal(t, tt.isNotFound, chronicleapi.IsNotFound(err), tt.name)
+		assert.Equal(t, tt.isAlreadyExists, chronicleapi.IsAlreadyExists(err), tt.name)
+		assert.Equal(t, tt.isPermissionDenied, chronicleapi.IsPermissionDenied(err), tt.name)
+		assert.Equal(t, tt.isInvalidArgument, chronicleapi.IsInvalidArgument(err), tt.name)
+		assert.Equal(t, tt.isExhausted, chronicleapi.IsResourceExhausted(err), tt.name)
+		assert.Equal(t, tt.expectedViolations, apiErr.FieldViolations(), tt.name)
+		retry, ok := apiErr.RetryAfter()
+		assert.Equal(t, tt.expectedRetry != 0, ok, tt.name)
+		assert.Equal(t, tt.expectedRetry, retry, tt.name)
+	}
+}
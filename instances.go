@@ -0,0 +1,30 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/calebryant/chronicle-api/resources/instances"
+)
+
+// Executes instances resource methods
+type InstancesService struct {
+	client *Client
+}
+
+var instancesGet = newAPIMethod("instances.get", V1, V1Alpha)
+
+// Gets an instance
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances/get
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances/get
+func (s *InstancesService) Get(ctx context.Context, instance *instances.InstanceResource) (*instances.InstanceResource, error) {
+	if instance == nil {
+		return nil, fmt.Errorf("missing instance resource")
+	}
+	result := &instances.InstanceResource{}
+	if err := s.client.do(ctx, instancesGet, instance.Name, instance.Get, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
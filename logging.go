@@ -0,0 +1,205 @@
+package chronicleapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultMaxLoggedBodySize = 2048
+
+// The value that replaces redacted header and JSON field values
+const Redacted = "REDACTED"
+
+// JSON fields redacted from logged bodies by default: parser and extension
+// CBN, raw logs (runParser log, log data, parsing error log data) and
+// reference list contents
+var DefaultRedactedFields = []string{"cbn", "cbnSnippet", "log", "data", "logData", "entries"}
+
+// Headers redacted from logged requests by default. The scheme of an
+// Authorization header is kept, ex. "Bearer REDACTED".
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "X-Goog-Api-Key"}
+
+// Logs every call with its method, resource, status, latency and truncated
+// request and response bodies. Bodies are logged as JSON with the values of
+// redacted fields replaced, at any depth, so that credentials, CBN and
+// customer logs never reach the log output.
+type RequestLogger struct {
+	Logger *slog.Logger
+	// The level calls are logged at, slog.LevelDebug by default
+	Level slog.Level
+	// Maximum number of body bytes logged, bodies are not logged if negative
+	MaxBodySize int
+	// JSON field names whose values are redacted from bodies
+	RedactFields []string
+	// Header names whose values are redacted
+	RedactHeaders []string
+}
+
+// Creates a RequestLogger with the default redactions that logs at debug level
+func NewRequestLogger(logger *slog.Logger) *RequestLogger {
+	return &RequestLogger{
+		Logger:        logger,
+		Level:         slog.LevelDebug,
+		MaxBodySize:   defaultMaxLoggedBodySize,
+		RedactFields:  DefaultRedactedFields,
+		RedactHeaders: DefaultRedactedHeaders,
+	}
+}
+
+// Logs every call at debug level with the default redactions, see
+// RequestLogger. A nil logger logs with slog.Default().
+func WithLogger(logger *slog.Logger) ClientOption {
+	return WithMiddleware(NewRequestLogger(logger).Middleware())
+}
+
+// Returns a Middleware that logs every call, with slog.Default() if the
+// Logger is nil
+func (l *RequestLogger) Middleware() Middleware {
+	logger := l.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			ctx := call.Request.Context()
+			if !logger.Enabled(ctx, l.Level) {
+				return next(call)
+			}
+			attrs := []slog.Attr{
+				slog.String("method", call.Method),
+				slog.String("path", call.Path.String()),
+				slog.String("http_method", call.Request.Method),
+				slog.String("url", call.Request.URL.String()),
+				l.headerAttr("request_headers", call.Request.Header),
+			}
+			if l.MaxBodySize >= 0 {
+				body, err := requestBody(call.Request)
+				if err != nil {
+					return nil, err
+				}
+				attrs = append(attrs, slog.String("request_body", l.body(body)))
+			}
+
+			start := time.Now()
+			resp, err := next(call)
+			attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, l.Level, "chronicle api call failed", attrs...)
+				return resp, err
+			}
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			if l.MaxBodySize >= 0 {
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+				attrs = append(attrs, slog.String("response_body", l.body(body)))
+			}
+			logger.LogAttrs(ctx, l.Level, "chronicle api call", attrs...)
+			return resp, nil
+		}
+	}
+}
+
+// Returns a copy of the request body, leaving the request's body unread
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Returns the headers as a log group with redacted header values
+func (l *RequestLogger) headerAttr(key string, header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if slices.ContainsFunc(l.RedactHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+			value = redactHeader(value)
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group(key, attrs...)
+}
+
+// Redacts a header value, keeping the scheme of credentials, ex. "Bearer REDACTED"
+func redactHeader(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		return scheme + " " + Redacted
+	}
+	return Redacted
+}
+
+// Returns the redacted and truncated body. Bodies that are not JSON are not
+// logged because their contents cannot be redacted.
+func (l *RequestLogger) body(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	redactedBody, err := RedactJSON(body, l.RedactFields)
+	if err != nil {
+		return fmt.Sprintf("<%d bytes of non-JSON body>", len(body))
+	}
+	if len(redactedBody) > l.MaxBodySize {
+		return fmt.Sprintf("%s...<%d bytes truncated>", redactedBody[:l.MaxBodySize], len(redactedBody)-l.MaxBodySize)
+	}
+	return string(redactedBody)
+}
+
+// Returns the JSON body as compact JSON with sorted object keys and the
+// values of the fields replaced by Redacted at any depth, or nil if the body
+// is JSON null. The request logger and the chronicletest cassettes both
+// redact bodies with it.
+func RedactJSON(body []byte, fields []string) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(redact(v, fields))
+}
+
+// Replaces the values of the fields in a decoded JSON value
+func redact(v interface{}, fields []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if slices.Contains(fields, key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(value, fields)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value, fields)
+		}
+	}
+	return v
+}
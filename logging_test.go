@@ -0,0 +1,167 @@
+package chronicleapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/parserextensions"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+	"github.com/calebryant/chronicle-api/resources/validationreports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	parserPath := "projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers"
+	var buf bytes.Buffer
+	requestLogger := chronicleapi.NewRequestLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	requestLogger.MaxBodySize = 150
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1alpha/" + parserPath:
+			fmt.Fprintf(w, `{"name": "%s/12345", "state": "ACTIVE", "cbn": "c2VjcmV0IGNibg=="}`, parserPath)
+		default:
+			fmt.Fprintf(w, `{"name": "%s", "displayName": "%s"}`, strings.TrimPrefix(r.URL.Path, "/v1alpha/"), strings.Repeat("a", 200))
+		}
+	}, chronicleapi.WithMiddleware(
+		chronicleapi.HeaderMiddleware(http.Header{"Authorization": {"Bearer secrettoken"}}),
+		requestLogger.Middleware(),
+	))
+	ctx := context.Background()
+
+	parser := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "")
+	parser.Cbn = []byte("secret cbn")
+	created, err := client.Parsers().Create(ctx, parser)
+	require.NoError(t, err)
+	// the response body is still decoded after it is logged
+	assert.Equal(t, []byte("secret cbn"), created.Cbn)
+	_, err = client.LogTypes().Get(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "secrettoken")
+	assert.NotContains(t, buf.String(), "c2VjcmV0IGNibg==")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var create map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &create))
+	assert.Equal(t, "DEBUG", create["level"])
+	assert.Equal(t, "parsers.create", create["method"])
+	assert.Equal(t, parserPath, create["path"])
+	assert.Equal(t, http.MethodPost, create["http_method"])
+	assert.Equal(t, float64(http.StatusOK), create["status"])
+	assert.Contains(t, create, "latency")
+	assert.Equal(t, "Bearer REDACTED", create["request_headers"].(map[string]interface{})["Authorization"])
+	assert.Contains(t, create["request_body"], `"cbn":"REDACTED"`)
+	assert.Contains(t, create["response_body"], `"cbn":"REDACTED"`)
+	assert.Contains(t, create["response_body"], `"state":"ACTIVE"`)
+
+	var get map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &get))
+	assert.Equal(t, "logTypes.get", get["method"])
+	assert.Equal(t, "", get["request_body"])
+	assert.Contains(t, get["response_body"], "bytes truncated>")
+}
+
+func TestRequestLoggerParserContent(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	report := validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "abc")
+	require.NoError(t, server.Add(report, &validationreports.ParsingErrorResource{
+		Name:    report.Name.Child(resources.ParsingErrorsResourceName, "0"),
+		LogData: []byte("secret log"),
+		Error:   &resources.Status{Code: 3, Message: "no match"},
+	}))
+	var buf bytes.Buffer
+	client, err := server.NewClient(chronicleapi.WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	extension := parserextensions.NewParserExtensionResource("testproject", "us", "testinstance", "WINEVTLOG", "")
+	extension.CbnSnippet = []byte("secret snippet")
+	extension.Log = []byte("secret log")
+	_, err = client.ParserExtensions().Create(ctx, extension)
+	require.NoError(t, err)
+	parsingErrors, err := client.ValidationReports().ListParsingErrors(ctx, report, "", "", "")
+	require.NoError(t, err)
+	require.Len(t, parsingErrors.ParsingErrors, 1)
+
+	for _, secret := range []string{"secret snippet", "secret log"} {
+		encoded := base64.StdEncoding.EncodeToString([]byte(secret))
+		assert.NotContains(t, buf.String(), encoded, secret)
+	}
+	assert.Contains(t, buf.String(), `\"cbnSnippet\":\"REDACTED\"`)
+	assert.Contains(t, buf.String(), `\"logData\":\"REDACTED\"`)
+}
+
+func TestRequestLoggerDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}, chronicleapi.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	_, err := client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestRequestLoggerNilLogger(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer slog.SetDefault(defaultLogger)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}, chronicleapi.WithLogger(nil))
+	_, err := client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `"method":"logTypes.get"`)
+}
+
+func TestRedactJSON(t *testing.T) {
+	tt := []struct {
+		name     string
+		body     string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "Nested fields",
+			body:     `{"parser": {"cbn": "Zmlyc3Q="}, "logs": [{"data": "bG9n", "name": "log1"}]}`,
+			expected: `{"logs":[{"data":"REDACTED","name":"log1"}],"parser":{"cbn":"REDACTED"}}`,
+		},
+		{
+			name:     "No redacted fields",
+			body:     `{"name": "parser1"}`,
+			expected: `{"name":"parser1"}`,
+		},
+		{
+			name: "Null",
+			body: `null`,
+		},
+		{
+			name: "Not JSON",
+			body: `cbn: secret`,
+			err:  true,
+		},
+	}
+	for _, tt := range tt {
+		redacted, err := chronicleapi.RedactJSON([]byte(tt.body), chronicleapi.DefaultRedactedFields)
+		if tt.err {
+			assert.Error(t, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, string(redacted), tt.name)
+	}
+}
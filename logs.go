@@ -0,0 +1,45 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources/logs"
+)
+
+// Executes logs resource methods
+type LogsService struct {
+	client *Client
+}
+
+var logsList = newAPIMethod("logs.list", V1Alpha)
+
+// Lists a single page of logs
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logs/list
+func (s *LogsService) List(ctx context.Context, log *logs.LogResource, pageSize, pageToken, filter string) (*logs.ListLogsResponse, error) {
+	if log == nil {
+		return nil, fmt.Errorf("missing log resource")
+	}
+	result := &logs.ListLogsResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return log.List(endpoint, pageSize, pageToken, filter)
+	}
+	if err := s.client.do(ctx, logsList, log.Name, build, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a pager over every log matching the filter
+func (s *LogsService) Pager(log *logs.LogResource, pageSize, filter string) *Pager[logs.LogResource] {
+	return newListPager(logsList, func(ctx context.Context, pageToken string) ([]logs.LogResource, string, error) {
+		resp, err := s.List(ctx, log, pageSize, pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Logs, resp.NextPageToken, nil
+	})
+}
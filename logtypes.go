@@ -0,0 +1,85 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+)
+
+// Executes logTypes resource methods
+type LogTypesService struct {
+	client *Client
+}
+
+var logTypesGet = newAPIMethod("logTypes.get", V1Alpha)
+
+// Gets a log type
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes/get
+func (s *LogTypesService) Get(ctx context.Context, logtype *logtypes.LogTypeResource) (*logtypes.LogTypeResource, error) {
+	if logtype == nil {
+		return nil, fmt.Errorf("missing log type resource")
+	}
+	result := &logtypes.LogTypeResource{}
+	if err := s.client.do(ctx, logTypesGet, logtype.Name, logtype.Get, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var logTypesList = newAPIMethod("logTypes.list", V1Alpha)
+
+// Lists a single page of log types
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes/list
+func (s *LogTypesService) List(ctx context.Context, logtype *logtypes.LogTypeResource, pageSize, pageToken string) (*logtypes.ListLogTypesResponse, error) {
+	if logtype == nil {
+		return nil, fmt.Errorf("missing log type resource")
+	}
+	result := &logtypes.ListLogTypesResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return logtype.List(endpoint, pageSize, pageToken)
+	}
+	if err := s.client.do(ctx, logTypesList, logtype.Name, build, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a pager over every log type
+func (s *LogTypesService) Pager(logtype *logtypes.LogTypeResource, pageSize string) *Pager[logtypes.LogTypeResource] {
+	return newListPager(logTypesList, func(ctx context.Context, pageToken string) ([]logtypes.LogTypeResource, string, error) {
+		resp, err := s.List(ctx, logtype, pageSize, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.LogTypes, resp.NextPageToken, nil
+	})
+}
+
+var logTypesRunParser = newAPIMethod("logTypes.runParser", V1Alpha)
+
+// Runs a parser, and optionally a parser extension CBN snippet, against
+// sample logs without creating either. The results are correlated with the
+// input logs, see logtypes.RunParserResponse.Correlate.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes/runParser
+func (s *LogTypesService) RunParser(ctx context.Context, logtype *logtypes.LogTypeResource, cbn, cbnSnippet []byte, logs [][]byte, statedumpAllowed bool) (*logtypes.RunParserResponse, error) {
+	if logtype == nil {
+		return nil, fmt.Errorf("missing log type resource")
+	}
+	result := &logtypes.RunParserResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return logtype.RunParser(endpoint, cbn, cbnSnippet, logs, statedumpAllowed)
+	}
+	if err := s.client.do(ctx, logTypesRunParser, logtype.Name, build, result); err != nil {
+		return nil, err
+	}
+	if err := result.Correlate(logs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
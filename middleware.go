@@ -0,0 +1,68 @@
+package chronicleapi
+
+import (
+	"net/http"
+
+	"github.com/calebryant/chronicle-api/resources"
+)
+
+// A Chronicle API call on its way through the client's middleware chain
+type Call struct {
+	// The API method name, ex. "logTypes.runParser"
+	Method string
+	// The resource the method targets
+	Path    resources.ResourcePath
+	Request *http.Request
+}
+
+// Sends a call and returns its response
+type Handler func(call *Call) (*http.Response, error)
+
+// Wraps a Handler with cross-cutting behavior, ex. retries, rate limiting,
+// logging or header injection. Middleware may replace call.Request before
+// passing the call on.
+type Middleware func(next Handler) Handler
+
+// Wraps the handler in the middleware, the first middleware is outermost
+func chain(handler Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// Makes every call wait on the rate limiter before it is sent
+func RateLimitMiddleware(limiter RateLimiter) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			if err := limiter.Wait(call.Request.Context(), call.Method, call.Path); err != nil {
+				return nil, err
+			}
+			return next(call)
+		}
+	}
+}
+
+// Sets the headers on every call
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			req := call.Request.Clone(call.Request.Context())
+			for key, values := range header {
+				req.Header[http.CanonicalHeaderKey(key)] = values
+			}
+			call.Request = req
+			return next(call)
+		}
+	}
+}
+
+// Sets the User-Agent header on every call
+func UserAgentMiddleware(userAgent string) Middleware {
+	return HeaderMiddleware(http.Header{"User-Agent": {userAgent}})
+}
+
+// Bills API quota to the project with the x-goog-user-project header
+func QuotaProjectMiddleware(project string) Middleware {
+	return HeaderMiddleware(http.Header{"X-Goog-User-Project": {project}})
+}
@@ -0,0 +1,75 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Records the order calls pass through it
+func recordingMiddleware(name string, events *[]string) chronicleapi.Middleware {
+	return func(next chronicleapi.Handler) chronicleapi.Handler {
+		return func(call *chronicleapi.Call) (*http.Response, error) {
+			*events = append(*events, fmt.Sprintf("%s before %s %s", name, call.Method, call.Path.String()))
+			resp, err := next(call)
+			*events = append(*events, fmt.Sprintf("%s after %d", name, resp.StatusCode))
+			return resp, err
+		}
+	}
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	var header http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		fmt.Fprint(w, `{}`)
+	}, chronicleapi.WithMiddleware(
+		recordingMiddleware("outer", new([]string)),
+		chronicleapi.UserAgentMiddleware("chronicle-tool/1.0"),
+		chronicleapi.QuotaProjectMiddleware("billingproject"),
+	))
+	_, err := client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+	assert.Equal(t, "chronicle-tool/1.0", header.Get("User-Agent"))
+	assert.Equal(t, "billingproject", header.Get("X-Goog-User-Project"))
+
+	var events []string
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}, chronicleapi.WithMiddleware(recordingMiddleware("first", &events)), chronicleapi.WithMiddleware(recordingMiddleware("second", &events)))
+	_, err = client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"first before logTypes.get projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG",
+		"second before logTypes.get projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG",
+		"second after 200",
+		"first after 200",
+	}, events)
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	attempts := 0
+	var events []string
+	retry := chronicleapi.NewRetryTransport(nil)
+	retry.InitialBackoff = time.Millisecond
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}, chronicleapi.WithMiddleware(retry.Middleware(), recordingMiddleware("inner", &events)))
+	_, err := client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	// middleware after the retry middleware sees every attempt
+	assert.Len(t, events, 4)
+}
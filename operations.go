@@ -0,0 +1,126 @@
+package chronicleapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/calebryant/chronicle-api/resources/operations"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultMaxPollInterval = 30 * time.Second
+)
+
+// Executes long-running operations resource methods
+type OperationsService struct {
+	client *Client
+}
+
+var operationsGet = newAPIMethod("operations.get", V1, V1Alpha)
+
+// Gets a long-running operation
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/get
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/get
+func (s *OperationsService) Get(ctx context.Context, op *operations.OperationResource) (*operations.OperationResource, error) {
+	if op == nil {
+		return nil, fmt.Errorf("missing operation resource")
+	}
+	result := &operations.OperationResource{}
+	if err := s.client.do(ctx, operationsGet, op.Name, op.Get, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var operationsList = newAPIMethod("operations.list", V1, V1Alpha)
+
+// Lists a single page of long-running operations
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/list
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/list
+func (s *OperationsService) List(ctx context.Context, op *operations.OperationResource, pageSize, pageToken, filter string) (*operations.ListOperationsResponse, error) {
+	if op == nil {
+		return nil, fmt.Errorf("missing operation resource")
+	}
+	result := &operations.ListOperationsResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return op.List(endpoint, pageSize, pageToken, filter)
+	}
+	if err := s.client.do(ctx, operationsList, op.Name, build, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a pager over every long-running operation matching the filter
+func (s *OperationsService) Pager(op *operations.OperationResource, pageSize, filter string) *Pager[operations.OperationResource] {
+	return newListPager(operationsList, func(ctx context.Context, pageToken string) ([]operations.OperationResource, string, error) {
+		resp, err := s.List(ctx, op, pageSize, pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Operations, resp.NextPageToken, nil
+	})
+}
+
+var operationsCancel = newAPIMethod("operations.cancel", V1, V1Alpha)
+
+// Starts asynchronous cancellation of a long-running operation
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/cancel
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/cancel
+func (s *OperationsService) Cancel(ctx context.Context, op *operations.OperationResource) error {
+	if op == nil {
+		return fmt.Errorf("missing operation resource")
+	}
+	return s.client.do(ctx, operationsCancel, op.Name, op.Cancel, nil)
+}
+
+var operationsDelete = newAPIMethod("operations.delete", V1, V1Alpha)
+
+// Deletes a long-running operation
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/delete
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/delete
+func (s *OperationsService) Delete(ctx context.Context, op *operations.OperationResource) error {
+	if op == nil {
+		return fmt.Errorf("missing operation resource")
+	}
+	return s.client.do(ctx, operationsDelete, op.Name, op.Delete, nil)
+}
+
+// Polls a long-running operation with backoff until it is done, then decodes
+// its response into response (if non-nil). A failed operation returns its
+// error as an *APIError. The wait is bounded by the context deadline.
+func (s *OperationsService) Wait(ctx context.Context, op *operations.OperationResource, response interface{}) (*operations.OperationResource, error) {
+	if op == nil {
+		return nil, fmt.Errorf("missing operation resource")
+	}
+	interval := s.client.pollInterval
+	for !op.Done {
+		if err := sleep(ctx, interval); err != nil {
+			return op, err
+		}
+		polled, err := s.Get(ctx, op)
+		if err != nil {
+			return op, err
+		}
+		op = polled
+		interval = min(interval*2, s.client.maxPollInterval)
+	}
+	if op.Error != nil {
+		return op, &APIError{Status: *op.Error}
+	}
+	if response != nil && len(op.Response) != 0 {
+		if err := json.Unmarshal(op.Response, response); err != nil {
+			return op, err
+		}
+	}
+	return op, nil
+}
@@ -0,0 +1,92 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources/operations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationsWait(t *testing.T) {
+	operationPath := "projects/testproject/locations/us/instances/testinstance/operations"
+	tt := []struct {
+		name          string
+		polls         int
+		result        string
+		expectedCount int
+		expectNotDone bool
+		expectFail    bool
+	}{
+		{
+			name:          "Done with response",
+			polls:         3,
+			result:        `"response": {"@type": "type.googleapis.com/test.Response", "count": 42}`,
+			expectedCount: 42,
+		},
+		{
+			name:       "Done with error",
+			polls:      1,
+			result:     `"error": {"code": 3, "message": "invalid parser", "status": "INVALID_ARGUMENT"}`,
+			expectFail: true,
+		},
+	}
+	for _, tt := range tt {
+		polls := 0
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1alpha/"+operationPath+"/12345", r.URL.Path)
+			polls++
+			if polls < tt.polls {
+				fmt.Fprintf(w, `{"name": "%s/12345"}`, operationPath)
+				return
+			}
+			fmt.Fprintf(w, `{"name": "%s/12345", "done": true, %s}`, operationPath, tt.result)
+		}, chronicleapi.WithPollInterval(time.Millisecond, 5*time.Millisecond))
+		response := struct {
+			Count int `json:"count"`
+		}{}
+		op, err := client.Operations().Wait(context.Background(), operations.NewOperationResource("testproject", "us", "testinstance", "12345"), &response)
+		assert.Equal(t, tt.polls, polls, tt.name)
+		require.True(t, op.Done, tt.name)
+		if tt.expectFail {
+			var apiErr *chronicleapi.APIError
+			require.ErrorAs(t, err, &apiErr, tt.name)
+			assert.True(t, chronicleapi.IsInvalidArgument(err), tt.name)
+			assert.Equal(t, "invalid parser", apiErr.Message, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expectedCount, response.Count, tt.name)
+	}
+}
+
+func TestOperationsWaitTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "projects/testproject/locations/us/instances/testinstance/operations/12345"}`)
+	}, chronicleapi.WithPollInterval(time.Millisecond, time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	op, err := client.Operations().Wait(ctx, operations.NewOperationResource("testproject", "us", "testinstance", "12345"), nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, op.Done)
+}
+
+func TestOperationsWaitZeroPollInterval(t *testing.T) {
+	var polls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		fmt.Fprint(w, `{"name": "projects/testproject/locations/us/instances/testinstance/operations/12345"}`)
+	}, chronicleapi.WithPollInterval(0, 0))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.Operations().Wait(ctx, operations.NewOperationResource("testproject", "us", "testinstance", "12345"), nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// the default initial interval is longer than the timeout
+	assert.Zero(t, polls.Load())
+}
@@ -0,0 +1,179 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fetches a single page of a List method given its page token, returning the
+// page items and the token of the next page ("" on the last page)
+type PageFunc[T any] func(ctx context.Context, pageToken string) ([]T, string, error)
+
+// Iterates over every item of a List method by following nextPageToken until
+// the last page
+//
+// Every iteration is recorded as a span named after the List method, ex.
+// "logTypes.list pages", with the number of pages fetched in its
+// chronicle.pages attribute. The calls of each page are children of the span.
+type Pager[T any] struct {
+	fetch PageFunc[T]
+	// The List method the pages are fetched with, ex. "logTypes.list"
+	method string
+	// Stops iterating after this many items, 0 for no limit
+	MaxItems int
+	// Fetches the next page in the background while the caller processes the
+	// current one
+	Prefetch bool
+	// Records the iteration spans, the global OpenTelemetry provider if nil
+	TracerProvider trace.TracerProvider
+}
+
+func NewPager[T any](fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{
+		fetch: fetch,
+	}
+}
+
+// Creates a pager over the pages of a List method
+func newListPager[T any](method *apiMethod, fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{
+		fetch:  fetch,
+		method: method.name,
+	}
+}
+
+type page[T any] struct {
+	items []T
+	err   error
+}
+
+// Returns an iterator over every item. Iteration stops at the first error,
+// which is yielded with the zero value of T.
+//
+//	for logtype, err := range client.LogTypes().Pager(logtype, "1000").All(ctx) {
+//		...
+//	}
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, span := p.startSpan(ctx)
+		pageCount := 0
+		defer func() {
+			span.SetAttributes(PagesKey.Int(pageCount))
+			span.End()
+		}()
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var pages iter.Seq[page[T]]
+		if p.Prefetch {
+			pages = p.prefetchPages(ctx)
+		} else {
+			pages = p.pages(ctx)
+		}
+		count := 0
+		for page := range pages {
+			if page.err != nil {
+				span.RecordError(page.err)
+				span.SetStatus(codes.Error, page.err.Error())
+				var zero T
+				yield(zero, page.err)
+				return
+			}
+			pageCount++
+			for _, item := range page.items {
+				if p.MaxItems > 0 && count >= p.MaxItems {
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				count++
+			}
+			if p.MaxItems > 0 && count >= p.MaxItems {
+				return
+			}
+		}
+	}
+}
+
+// Starts the span of an iteration
+func (p *Pager[T]) startSpan(ctx context.Context) (context.Context, trace.Span) {
+	tp := p.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	name := "pages"
+	var attrs []attribute.KeyValue
+	if p.method != "" {
+		name = p.method + " pages"
+		attrs = append(attrs, MethodKey.String(p.method))
+	}
+	return tp.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Collects every item into a slice
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	var items []T
+	for item, err := range p.All(ctx) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Fetches pages one at a time as the caller asks for them
+func (p *Pager[T]) pages(ctx context.Context) iter.Seq[page[T]] {
+	return func(yield func(page[T]) bool) {
+		pageToken := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(page[T]{err: err})
+				return
+			}
+			items, nextPageToken, err := p.fetch(ctx, pageToken)
+			if err == nil && nextPageToken != "" && nextPageToken == pageToken {
+				err = fmt.Errorf("page token %q repeated", pageToken)
+			}
+			if !yield(page[T]{items: items, err: err}) || err != nil || nextPageToken == "" {
+				return
+			}
+			pageToken = nextPageToken
+		}
+	}
+}
+
+// Fetches pages in a goroutine that stays one page ahead of the caller
+func (p *Pager[T]) prefetchPages(ctx context.Context) iter.Seq[page[T]] {
+	return func(yield func(page[T]) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		pageCh := make(chan page[T])
+		done := make(chan struct{})
+		defer func() {
+			cancel()
+			<-done
+		}()
+		go func() {
+			defer close(done)
+			defer close(pageCh)
+			for page := range p.pages(ctx) {
+				select {
+				case pageCh <- page:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		for page := range pageCh {
+			if !yield(page) {
+				return
+			}
+		}
+	}
+}
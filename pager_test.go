@@ -0,0 +1,158 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a page func over the integers [0, total) with pages of the given size
+func intPages(total, size int, fetched *int) chronicleapi.PageFunc[int] {
+	return func(ctx context.Context, pageToken string) ([]int, string, error) {
+		*fetched++
+		start := 0
+		if pageToken != "" {
+			start, _ = strconv.Atoi(pageToken)
+		}
+		var items []int
+		for i := start; i < min(start+size, total); i++ {
+			items = append(items, i)
+		}
+		if start+size >= total {
+			return items, "", nil
+		}
+		return items, strconv.Itoa(start + size), nil
+	}
+}
+
+func TestPager(t *testing.T) {
+	tt := []struct {
+		name            string
+		total           int
+		pageSize        int
+		maxItems        int
+		prefetch        bool
+		expectedItems   int
+		expectedFetches int
+	}{
+		{
+			name:            "All pages",
+			total:           10,
+			pageSize:        3,
+			expectedItems:   10,
+			expectedFetches: 4,
+		},
+		{
+			name:            "All pages (prefetch)",
+			total:           10,
+			pageSize:        3,
+			prefetch:        true,
+			expectedItems:   10,
+			expectedFetches: 4,
+		},
+		{
+			name:            "Max items",
+			total:           10,
+			pageSize:        3,
+			maxItems:        5,
+			expectedItems:   5,
+			expectedFetches: 2,
+		},
+		{
+			name:            "Max items on a page boundary",
+			total:           10,
+			pageSize:        3,
+			maxItems:        6,
+			expectedItems:   6,
+			expectedFetches: 2,
+		},
+		{
+			name:            "Empty",
+			total:           0,
+			pageSize:        3,
+			expectedItems:   0,
+			expectedFetches: 1,
+		},
+	}
+	for _, tt := range tt {
+		fetched := 0
+		pager := chronicleapi.NewPager(intPages(tt.total, tt.pageSize, &fetched))
+		pager.MaxItems = tt.maxItems
+		pager.Prefetch = tt.prefetch
+		items, err := pager.Collect(context.Background())
+		require.NoError(t, err, tt.name)
+		assert.Len(t, items, tt.expectedItems, tt.name)
+		for i, item := range items {
+			assert.Equal(t, i, item, tt.name)
+		}
+		assert.Equal(t, tt.expectedFetches, fetched, tt.name)
+	}
+}
+
+func TestPagerStopEarly(t *testing.T) {
+	fetched := 0
+	pager := chronicleapi.NewPager(intPages(100, 10, &fetched))
+	pager.Prefetch = true
+	for item, err := range pager.All(context.Background()) {
+		require.NoError(t, err)
+		if item == 15 {
+			break
+		}
+	}
+	// the second page is being processed and at most one more was prefetched
+	assert.LessOrEqual(t, fetched, 3)
+}
+
+func TestPagerErrors(t *testing.T) {
+	pageErr := errors.New("page error")
+	pager := chronicleapi.NewPager(func(ctx context.Context, pageToken string) ([]int, string, error) {
+		if pageToken == "" {
+			return []int{1, 2}, "next", nil
+		}
+		return nil, "", pageErr
+	})
+	items, err := pager.Collect(context.Background())
+	assert.ErrorIs(t, err, pageErr)
+	assert.Equal(t, []int{1, 2}, items)
+
+	repeating := chronicleapi.NewPager(func(ctx context.Context, pageToken string) ([]int, string, error) {
+		return []int{1}, "same", nil
+	})
+	_, err = repeating.Collect(context.Background())
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fetched := 0
+	_, err = chronicleapi.NewPager(intPages(10, 3, &fetched)).Collect(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, fetched)
+}
+
+func TestLogTypesPager(t *testing.T) {
+	logtypePath := "projects/testproject/locations/us/instances/testinstance/logTypes"
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1alpha/"+logtypePath, r.URL.Path)
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			fmt.Fprintf(w, `{"logTypes": [{"name": "%s/WINEVTLOG"}], "nextPageToken": "page2"}`, logtypePath)
+		case "page2":
+			fmt.Fprintf(w, `{"logTypes": [{"name": "%s/PAN_FIREWALL"}]}`, logtypePath)
+		}
+	})
+	pager := client.LogTypes().Pager(logtypes.NewLogTypeResource("testproject", "us", "testinstance", ""), "1")
+	var names []string
+	for logtype, err := range pager.All(context.Background()) {
+		require.NoError(t, err)
+		names = append(names, logtype.Name.String())
+	}
+	assert.Equal(t, []string{logtypePath + "/WINEVTLOG", logtypePath + "/PAN_FIREWALL"}, names)
+}
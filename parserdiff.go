@@ -0,0 +1,250 @@
+package chronicleapi
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logs"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+)
+
+const DefaultParserDiffSampleSize = 100
+
+// Options for comparing a candidate parser with the active parser
+type ParserDiffOptions struct {
+	// The logs to parse, if nil a sample of the log type's logs is listed
+	Logs [][]byte
+	// The number of logs to list, DefaultParserDiffSampleSize if 0
+	SampleSize int
+	// The filter of the listed logs, ex. environmentNamespace = "prod"
+	LogFilter string
+	// A parser extension CBN snippet run with both parsers
+	Extension []byte
+	// Limits for splitting the logs into RunParser requests
+	Batch RunParserBatchOptions
+}
+
+// How the output of a candidate parser differs from the active parser's
+type ParserDiff struct {
+	Active *parsers.ParserResource
+	// One entry per log, in sample order
+	Logs  []LogDiff
+	Stats ParserDiffStats
+	// The RunParser chunks that failed for each parser. The logs of a failed
+	// chunk are compared with the chunk's error status as their parse error.
+	ActiveChunkErrors, CandidateChunkErrors RunParserChunkErrors
+}
+
+// How the parsed output of one log differs
+type LogDiff struct {
+	Index int
+	Log   []byte
+	// The differing fields of the parsed events, old values are the active
+	// parser's, ex. "events[0].event.principal.ip[1]"
+	Diffs []logtypes.FieldDiff
+	// The parse errors of the active and candidate parsers
+	ActiveError, CandidateError *resources.Status
+}
+
+// Aggregate statistics of a ParserDiff
+type ParserDiffStats struct {
+	// The number of logs compared
+	Logs int
+	// The number of logs whose parsed output differs
+	ChangedLogs int
+	// The number of logs each parser failed to parse
+	ActiveErrors, CandidateErrors int
+	// The total number of added, removed and changed field values
+	Added, Removed, Changed int
+	// Statistics of each differing field, keyed by field path without event
+	// or repeated field indexes, ex. "event.principal.ip"
+	Fields map[string]*FieldDiffStats
+}
+
+// Statistics of one differing field
+type FieldDiffStats struct {
+	Added, Removed, Changed int
+	// The number of logs in which the field differs
+	Logs int
+}
+
+// Returns true if the parsed output of the log differs
+func (d *LogDiff) Changed() bool {
+	return len(d.Diffs) > 0 || (d.ActiveError == nil) != (d.CandidateError == nil)
+}
+
+// Returns the differing field paths ordered by the number of logs in which
+// they differ, most first
+func (s *ParserDiffStats) FieldPaths() []string {
+	paths := make([]string, 0, len(s.Fields))
+	for path := range s.Fields {
+		paths = append(paths, path)
+	}
+	slices.SortFunc(paths, func(a, b string) int {
+		if c := cmp.Compare(s.Fields[b].Logs, s.Fields[a].Logs); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return paths
+}
+
+// Runs the log type's active parser and a candidate parser on the same
+// logs and compares their parsed UDM field by field, ex. before activating
+// the candidate. Without sample logs in the options, the log type's logs are
+// listed.
+func (s *ParsersService) Diff(ctx context.Context, logtype *logtypes.LogTypeResource, candidate []byte, opts ParserDiffOptions) (*ParserDiff, error) {
+	if logtype == nil {
+		return nil, fmt.Errorf("missing log type resource")
+	}
+	collection := &parsers.ParserResource{Name: logtype.Name.Child(resources.ParsersResourceName, "")}
+	active, err := s.Pager(collection, "", parsers.Filter(parsers.StateActive, "")).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing active parser: %w", err)
+	}
+	if len(active) == 0 {
+		return nil, fmt.Errorf("log type %s has no active parser", logtype.Name.ID())
+	}
+	// the list view may leave out the cbn
+	activeParser, err := s.Get(ctx, &active[0])
+	if err != nil {
+		return nil, fmt.Errorf("getting active parser: %w", err)
+	}
+	if len(activeParser.Cbn) == 0 {
+		return nil, fmt.Errorf("active parser %s has no cbn", activeParser.Name)
+	}
+
+	sample := opts.Logs
+	if sample == nil {
+		if sample, err = s.sampleLogs(ctx, logtype, opts); err != nil {
+			return nil, err
+		}
+	}
+	diff := &ParserDiff{
+		Active: activeParser,
+		Logs:   make([]LogDiff, len(sample)),
+		Stats:  ParserDiffStats{Logs: len(sample), Fields: map[string]*FieldDiffStats{}},
+	}
+	activeResp, err := s.client.LogTypes().RunParserBatch(ctx, logtype, activeParser.Cbn, opts.Extension, sample, false, opts.Batch)
+	if err != nil && !errors.As(err, &diff.ActiveChunkErrors) {
+		return nil, fmt.Errorf("running active parser: %w", err)
+	}
+	candidateResp, err := s.client.LogTypes().RunParserBatch(ctx, logtype, candidate, opts.Extension, sample, false, opts.Batch)
+	if err != nil && !errors.As(err, &diff.CandidateChunkErrors) {
+		return nil, fmt.Errorf("running candidate parser: %w", err)
+	}
+	for i := range sample {
+		logDiff, err := diffResults(&activeResp.RunParserResults[i], &candidateResp.RunParserResults[i])
+		if err != nil {
+			return nil, err
+		}
+		diff.Logs[i] = *logDiff
+		diff.Stats.add(logDiff)
+	}
+	return diff, nil
+}
+
+// Lists up to SampleSize of the log type's logs, decoding their base64 data
+func (s *ParsersService) sampleLogs(ctx context.Context, logtype *logtypes.LogTypeResource, opts ParserDiffOptions) ([][]byte, error) {
+	size := opts.SampleSize
+	if size <= 0 {
+		size = DefaultParserDiffSampleSize
+	}
+	collection := &logs.LogResource{Name: logtype.Name.Child(resources.LogsResourceName, "")}
+	var sample [][]byte
+	for log, err := range s.client.Logs().Pager(collection, strconv.Itoa(min(size, 1000)), opts.LogFilter).All(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("listing sample logs: %w", err)
+		}
+		data, err := base64.StdEncoding.DecodeString(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding sample log %s: %w", log.Name, err)
+		}
+		sample = append(sample, data)
+		if len(sample) == size {
+			break
+		}
+	}
+	return sample, nil
+}
+
+// Compares the parsed events of two results of the same log event by event
+func diffResults(active, candidate *logtypes.RunParserResult) (*LogDiff, error) {
+	logDiff := &LogDiff{
+		Index:          active.Index,
+		Log:            active.Log,
+		ActiveError:    active.Error,
+		CandidateError: candidate.Error,
+	}
+	activeEvents, candidateEvents := parsedEvents(active), parsedEvents(candidate)
+	for i := range max(len(activeEvents), len(candidateEvents)) {
+		var activeEvent, candidateEvent *logtypes.EventOrEntity
+		if i < len(activeEvents) {
+			activeEvent = &activeEvents[i]
+		}
+		if i < len(candidateEvents) {
+			candidateEvent = &candidateEvents[i]
+		}
+		diffs, err := logtypes.Diff(activeEvent, candidateEvent)
+		if err != nil {
+			return nil, err
+		}
+		for _, diff := range diffs {
+			diff.Path = strings.TrimSuffix(fmt.Sprintf("events[%d].%s", i, diff.Path), ".")
+			logDiff.Diffs = append(logDiff.Diffs, diff)
+		}
+	}
+	return logDiff, nil
+}
+
+func parsedEvents(result *logtypes.RunParserResult) []logtypes.EventOrEntity {
+	if result.ParsedEvents == nil {
+		return nil
+	}
+	return result.ParsedEvents.Events
+}
+
+func (s *ParserDiffStats) add(logDiff *LogDiff) {
+	if logDiff.ActiveError != nil {
+		s.ActiveErrors++
+	}
+	if logDiff.CandidateError != nil {
+		s.CandidateErrors++
+	}
+	if logDiff.Changed() {
+		s.ChangedLogs++
+	}
+	counted := map[string]bool{}
+	for _, diff := range logDiff.Diffs {
+		// drop the events[i] prefix along with repeated field indexes
+		_, path, _ := strings.Cut(diff.Field(), ".")
+		stats, ok := s.Fields[path]
+		if !ok {
+			stats = &FieldDiffStats{}
+			s.Fields[path] = stats
+		}
+		switch diff.Kind {
+		case logtypes.DiffAdded:
+			stats.Added++
+			s.Added++
+		case logtypes.DiffRemoved:
+			stats.Removed++
+			s.Removed++
+		case logtypes.DiffChanged:
+			stats.Changed++
+			s.Changed++
+		}
+		if !counted[path] {
+			counted[path] = true
+			stats.Logs++
+		}
+	}
+}
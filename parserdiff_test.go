@@ -0,0 +1,124 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logs"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserDiff(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	// the candidate parser adds a hostname, changes the event type of logs
+	// with "http" and fails on "bad"
+	server.SetParseFunc(func(cbn, cbnSnippet, log []byte, statedumpAllowed bool) logtypes.RunParserResult {
+		candidate := string(cbn) == "candidate"
+		if candidate && string(log) == "bad" {
+			return logtypes.RunParserResult{Error: &resources.Status{Code: 3, Message: "no match"}}
+		}
+		eventType := "NETWORK_CONNECTION"
+		principal := map[string]interface{}{"ip": []interface{}{"10.0.0.1"}}
+		if candidate {
+			principal["hostname"] = "host"
+			if string(log) == "http" {
+				eventType = "NETWORK_HTTP"
+			}
+		}
+		event := logtypes.UDM{"metadata": map[string]interface{}{"eventType": eventType}, "principal": principal}
+		return logtypes.RunParserResult{ParsedEvents: &logtypes.ParsedEvents{Events: []logtypes.EventOrEntity{{Event: event}}}}
+	})
+	active := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "active")
+	active.State = parsers.StateActive
+	active.Cbn = []byte("active")
+	inactive := parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "inactive")
+	inactive.State = parsers.StateInactive
+	inactive.Cbn = []byte("candidate")
+	require.NoError(t, server.Add(inactive, active))
+	for i, data := range []string{"http", "bad", "dns", "other"} {
+		log := logs.NewLogResource("testproject", "us", "testinstance", "WINEVTLOG", fmt.Sprintf("log%d", i))
+		log.Data = base64.StdEncoding.EncodeToString([]byte(data))
+		require.NoError(t, server.Add(log))
+	}
+	undecodable := logs.NewLogResource("testproject", "us", "testinstance", "WINEVTLOG", "undecodable")
+	undecodable.Data = "not base64"
+	assert.Error(t, server.Add(undecodable))
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	ctx := context.Background()
+
+	diff, err := client.Parsers().Diff(ctx, logtype, []byte("candidate"), chronicleapi.ParserDiffOptions{SampleSize: 3})
+	require.NoError(t, err)
+	assert.Equal(t, active.Name, diff.Active.Name)
+	require.Len(t, diff.Logs, 3)
+
+	httpLog := diff.Logs[0]
+	assert.Equal(t, []byte("http"), httpLog.Log)
+	assert.Equal(t, []logtypes.FieldDiff{
+		{Path: "events[0].event.metadata.eventType", Kind: logtypes.DiffChanged, Old: "NETWORK_CONNECTION", New: "NETWORK_HTTP"},
+		{Path: "events[0].event.principal.hostname", Kind: logtypes.DiffAdded, New: "host"},
+	}, httpLog.Diffs)
+	bad := diff.Logs[1]
+	assert.True(t, bad.Changed())
+	assert.Nil(t, bad.ActiveError)
+	require.NotNil(t, bad.CandidateError)
+	assert.Equal(t, "no match", bad.CandidateError.Message)
+
+	stats := diff.Stats
+	assert.Equal(t, 3, stats.Logs)
+	assert.Equal(t, 3, stats.ChangedLogs)
+	assert.Equal(t, 0, stats.ActiveErrors)
+	assert.Equal(t, 1, stats.CandidateErrors)
+	assert.Equal(t, 2, stats.Added)
+	assert.Equal(t, 2, stats.Removed)
+	assert.Equal(t, 1, stats.Changed)
+	assert.Equal(t, &chronicleapi.FieldDiffStats{Added: 2, Logs: 2}, stats.Fields["event.principal.hostname"])
+	assert.Equal(t, &chronicleapi.FieldDiffStats{Removed: 1, Changed: 1, Logs: 2}, stats.Fields["event.metadata.eventType"])
+	assert.Equal(t, []string{"event.metadata.eventType", "event.principal.hostname", "event.principal.ip"}, stats.FieldPaths())
+
+	diff, err = client.Parsers().Diff(ctx, logtype, []byte("active"), chronicleapi.ParserDiffOptions{Logs: [][]byte{[]byte("http")}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.Stats.Logs)
+	assert.Equal(t, 0, diff.Stats.ChangedLogs)
+	assert.Empty(t, diff.Logs[0].Diffs)
+
+	// a failed chunk keeps the results of the other chunks
+	server.FailNext("logTypes.runParser", 1, chronicletest.NewStatus(http.StatusBadRequest, "bad chunk"))
+	diff, err = client.Parsers().Diff(ctx, logtype, []byte("active"), chronicleapi.ParserDiffOptions{
+		Logs:  [][]byte{[]byte("http"), []byte("dns")},
+		Batch: chronicleapi.RunParserBatchOptions{MaxLogs: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, diff.ActiveChunkErrors, 1)
+	assert.Empty(t, diff.CandidateChunkErrors)
+	failed := diff.Logs[diff.ActiveChunkErrors[0].Start]
+	require.NotNil(t, failed.ActiveError)
+	assert.Equal(t, "bad chunk", failed.ActiveError.Message)
+	assert.Equal(t, 1, diff.Stats.ActiveErrors)
+	assert.Equal(t, 1, diff.Stats.ChangedLogs)
+
+	_, err = client.Parsers().Diff(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "PAN_FIREWALL"), []byte("candidate"), chronicleapi.ParserDiffOptions{})
+	assert.ErrorContains(t, err, "no active parser")
+	// the active parser is fetched in full, and must have a cbn
+	assert.Equal(t, 3, server.Calls("parsers.get"))
+	nocbn := parsers.NewParserResource("testproject", "us", "testinstance", "DNS", "nocbn")
+	nocbn.State = parsers.StateActive
+	require.NoError(t, server.Add(nocbn))
+	runParserCalls := server.Calls("logTypes.runParser")
+	_, err = client.Parsers().Diff(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "DNS"), []byte("candidate"), chronicleapi.ParserDiffOptions{Logs: [][]byte{[]byte("dns")}})
+	assert.ErrorContains(t, err, "has no cbn")
+	assert.Equal(t, 0, server.Calls("logTypes.runParser")-runParserCalls)
+	_, err = client.Parsers().Diff(ctx, nil, []byte("candidate"), chronicleapi.ParserDiffOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources/parserextensions"
+	"github.com/calebryant/chronicle-api/resources/validationreports"
+)
+
+// Executes parserExtensions resource methods
+type ParserExtensionsService struct {
+	client *Client
+}
+
+var parserExtensionsCreate = newAPIMethod("parserExtensions.create", V1Alpha)
+
+// Creates a parser extension, which is validated against its sample log
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/create
+func (s *ParserExtensionsService) Create(ctx context.Context, extension *parserextensions.ParserExtensionResource) (*parserextensions.ParserExtensionResource, error) {
+	if extension == nil {
+		return nil, fmt.Errorf("missing parser extension resource")
+	}
+	result := &parserextensions.ParserExtensionResource{}
+	if err := s.client.do(ctx, parserExtensionsCreate, extension.Name, extension.Create, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var parserExtensionsGet = newAPIMethod("parserExtensions.get", V1Alpha)
+
+// Gets a parser extension
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/get
+func (s *ParserExtensionsService) Get(ctx context.Context, extension *parserextensions.ParserExtensionResource) (*parserextensions.ParserExtensionResource, error) {
+	if extension == nil {
+		return nil, fmt.Errorf("missing parser extension resource")
+	}
+	result := &parserextensions.ParserExtensionResource{}
+	if err := s.client.do(ctx, parserExtensionsGet, extension.Name, extension.Get, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var parserExtensionsList = newAPIMethod("parserExtensions.list", V1Alpha)
+
+// Lists a single page of parser extensions matching the filter
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/list
+func (s *ParserExtensionsService) List(ctx context.Context, extension *parserextensions.ParserExtensionResource, pageSize, pageToken, filter string) (*parserextensions.ListParserExtensionsResponse, error) {
+	if extension == nil {
+		return nil, fmt.Errorf("missing parser extension resource")
+	}
+	result := &parserextensions.ListParserExtensionsResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return extension.List(endpoint, pageSize, pageToken, filter)
+	}
+	if err := s.client.do(ctx, parserExtensionsList, extension.Name, build, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a pager over every parser extension matching the filter
+func (s *ParserExtensionsService) Pager(extension *parserextensions.ParserExtensionResource, pageSize, filter string) *Pager[parserextensions.ParserExtensionResource] {
+	return newListPager(parserExtensionsList, func(ctx context.Context, pageToken string) ([]parserextensions.ParserExtensionResource, string, error) {
+		resp, err := s.List(ctx, extension, pageSize, pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.ParserExtensions, resp.NextPageToken, nil
+	})
+}
+
+var parserExtensionsDelete = newAPIMethod("parserExtensions.delete", V1Alpha)
+
+// Deletes a parser extension
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/delete
+func (s *ParserExtensionsService) Delete(ctx context.Context, extension *parserextensions.ParserExtensionResource) error {
+	if extension == nil {
+		return fmt.Errorf("missing parser extension resource")
+	}
+	return s.client.do(ctx, parserExtensionsDelete, extension.Name, extension.Delete, nil)
+}
+
+var parserExtensionsActivate = newAPIMethod("parserExtensions.activate", V1Alpha)
+
+// Activates a validated parser extension, making it the live extension of
+// its log type
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/activate
+func (s *ParserExtensionsService) Activate(ctx context.Context, extension *parserextensions.ParserExtensionResource) error {
+	if extension == nil {
+		return fmt.Errorf("missing parser extension resource")
+	}
+	return s.client.do(ctx, parserExtensionsActivate, extension.Name, extension.Activate, nil)
+}
+
+// Gets the validation report of a parser extension. The extension is fetched
+// first if its ValidationReport name is not set.
+func (s *ParserExtensionsService) ValidationReport(ctx context.Context, extension *parserextensions.ParserExtensionResource) (*validationreports.ValidationReportResource, error) {
+	if extension == nil {
+		return nil, fmt.Errorf("missing parser extension resource")
+	}
+	if extension.ValidationReport.IsEmpty() {
+		var err error
+		if extension, err = s.Get(ctx, extension); err != nil {
+			return nil, err
+		}
+		if extension.ValidationReport.IsEmpty() {
+			return nil, fmt.Errorf("parser extension %s has no validation report", extension.Name.String())
+		}
+	}
+	return s.client.ValidationReports().Get(ctx, &validationreports.ValidationReportResource{Name: extension.ValidationReport})
+}
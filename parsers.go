@@ -0,0 +1,134 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources/parsers"
+)
+
+// Executes parsers resource methods
+type ParsersService struct {
+	client *Client
+}
+
+var parsersCreate = newAPIMethod("parsers.create", V1Alpha)
+
+// Creates a parser
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/create
+func (s *ParsersService) Create(ctx context.Context, parser *parsers.ParserResource) (*parsers.ParserResource, error) {
+	if parser == nil {
+		return nil, fmt.Errorf("missing parser resource")
+	}
+	result := &parsers.ParserResource{}
+	if err := s.client.do(ctx, parsersCreate, parser.Name, parser.Create, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var parsersGet = newAPIMethod("parsers.get", V1Alpha)
+
+// Gets a parser
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/get
+func (s *ParsersService) Get(ctx context.Context, parser *parsers.ParserResource) (*parsers.ParserResource, error) {
+	if parser == nil {
+		return nil, fmt.Errorf("missing parser resource")
+	}
+	result := &parsers.ParserResource{}
+	if err := s.client.do(ctx, parsersGet, parser.Name, parser.Get, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var parsersList = newAPIMethod("parsers.list", V1Alpha)
+
+// Lists a single page of parsers matching the filter, see parsers.Filter. The
+// log type of the parser resource may be the "-" wildcard to list the
+// parsers of every log type.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/list
+func (s *ParsersService) List(ctx context.Context, parser *parsers.ParserResource, pageSize, pageToken, filter string) (*parsers.ListParsersResponse, error) {
+	if parser == nil {
+		return nil, fmt.Errorf("missing parser resource")
+	}
+	result := &parsers.ListParsersResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return parser.List(endpoint, pageSize, pageToken, filter)
+	}
+	if err := s.client.do(ctx, parsersList, parser.Name, build, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a pager over every parser matching the filter
+func (s *ParsersService) Pager(parser *parsers.ParserResource, pageSize, filter string) *Pager[parsers.ParserResource] {
+	return newListPager(parsersList, func(ctx context.Context, pageToken string) ([]parsers.ParserResource, string, error) {
+		resp, err := s.List(ctx, parser, pageSize, pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Parsers, resp.NextPageToken, nil
+	})
+}
+
+var parsersDelete = newAPIMethod("parsers.delete", V1Alpha)
+
+// Deletes a parser. An active parser is only deleted if force is true.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/delete
+func (s *ParsersService) Delete(ctx context.Context, parser *parsers.ParserResource, force bool) error {
+	if parser == nil {
+		return fmt.Errorf("missing parser resource")
+	}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return parser.Delete(endpoint, force)
+	}
+	return s.client.do(ctx, parsersDelete, parser.Name, build, nil)
+}
+
+var parsersCopy = newAPIMethod("parsers.copy", V1Alpha)
+
+// Copies a prebuilt parser into a new custom parser and returns the copy
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/copy
+func (s *ParsersService) Copy(ctx context.Context, parser *parsers.ParserResource) (*parsers.ParserResource, error) {
+	if parser == nil {
+		return nil, fmt.Errorf("missing parser resource")
+	}
+	result := &parsers.ParserResource{}
+	if err := s.client.do(ctx, parsersCopy, parser.Name, parser.Copy, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var parsersActivate = newAPIMethod("parsers.activate", V1Alpha)
+
+// Activates a parser
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/activate
+func (s *ParsersService) Activate(ctx context.Context, parser *parsers.ParserResource) error {
+	if parser == nil {
+		return fmt.Errorf("missing parser resource")
+	}
+	return s.client.do(ctx, parsersActivate, parser.Name, parser.Activate, nil)
+}
+
+var parsersDeactivate = newAPIMethod("parsers.deactivate", V1Alpha)
+
+// Deactivates a parser
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/deactivate
+func (s *ParsersService) Deactivate(ctx context.Context, parser *parsers.ParserResource) error {
+	if parser == nil {
+		return fmt.Errorf("missing parser resource")
+	}
+	return s.client.do(ctx, parsersDeactivate, parser.Name, parser.Deactivate, nil)
+}
@@ -0,0 +1,84 @@
+package parsertest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Error    *junitMessage   `xml:"error,omitempty"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// Writes the results as a JUnit XML report with a test suite per log type
+// and a test case per log. Logs that differ from their golden outputs are
+// failures and logs that could not be compared are errors.
+func WriteJUnit(w io.Writer, results []*CaseResult) error {
+	report := junitTestSuites{}
+	for _, result := range results {
+		suite := junitTestSuite{
+			Name: result.LogType,
+			Time: fmt.Sprintf("%.3f", result.Duration.Seconds()),
+		}
+		if result.Err != nil {
+			suite.Errors++
+			suite.Error = &junitMessage{Message: result.Err.Error()}
+		}
+		for _, log := range result.Logs {
+			testCase := junitTestCase{Name: log.Name, ClassName: result.LogType}
+			switch {
+			case log.Err != nil:
+				suite.Errors++
+				testCase.Error = &junitMessage{Message: log.Err.Error()}
+			case len(log.Diffs) > 0:
+				suite.Failures++
+				testCase.Failure = &junitMessage{
+					Message: fmt.Sprintf("%d fields differ from %s", len(log.Diffs), log.Golden),
+					Body:    FormatDiffs(log.Diffs),
+				}
+			}
+			suite.Tests++
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,323 @@
+// Package parsertest runs parsers against directories of sample logs and
+// compares the parsed UDM with golden outputs, ex. in a test:
+//
+//	var update = parsertest.UpdateFlag()
+//
+//	func TestParsers(t *testing.T) {
+//		runner := &parsertest.Runner{Client: client, Project: "project", Location: "us", Instance: "instance", Update: *update}
+//		results := runner.Test(t, "testdata")
+//		f, _ := os.Create("junit.xml")
+//		defer f.Close()
+//		parsertest.WriteJUnit(f, results)
+//	}
+//
+// Each log type has a directory in the test data directory:
+//
+//	testdata/<LOGTYPE>/parser.conf      the parser CBN
+//	testdata/<LOGTYPE>/extension.conf   an optional parser extension CBN snippet
+//	testdata/<LOGTYPE>/logs/<name>      one raw log per file
+//	testdata/<LOGTYPE>/expected/<name>.json
+//
+// A golden output is named after its log file without the extension, so log
+// file names must differ by more than their extension. It is the JSON
+// encoded RunParserResult of the log without statedump results. Run the
+// tests with -update, or with PARSERTEST_UPDATE=1, to write the golden outputs
+// from the actual results.
+package parsertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+)
+
+const (
+	parserFile    = "parser.conf"
+	extensionFile = "extension.conf"
+	logsDir       = "logs"
+	expectedDir   = "expected"
+)
+
+// The environment variable that sets the default of the -update flag
+const UpdateEnv = "PARSERTEST_UPDATE"
+
+var updateFlag = sync.OnceValue(func() *bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return flag.Bool("update", update, "rewrite the parsertest golden outputs")
+})
+
+// Registers the -update flag, which defaults to the PARSERTEST_UPDATE
+// environment variable, and returns it for Runner.Update. Call it from a
+// package-level variable of the test package so the flag is registered
+// before the test flags are parsed. Test packages that define their own
+// -update flag set Runner.Update from it instead.
+func UpdateFlag() *bool {
+	return updateFlag()
+}
+
+// The parser and sample logs of a log type
+type Case struct {
+	LogType string
+	// The log type's directory
+	Dir string
+	// The parser CBN
+	Parser []byte
+	// The parser extension CBN snippet, nil if there is none
+	Extension []byte
+	Logs      []Log
+}
+
+// A sample log and its golden output
+type Log struct {
+	// The log file name without its extension
+	Name string
+	Data []byte
+	// The path of the golden output
+	Golden string
+}
+
+// The result of running a log type's parser against its sample logs
+type CaseResult struct {
+	LogType  string
+	Duration time.Duration
+	// Set if the parser could not be run, ex. the request failed
+	Err  error
+	Logs []LogResult
+}
+
+// The result of comparing a log's parsed output with its golden output
+type LogResult struct {
+	Name string
+	// The path of the golden output
+	Golden string
+	// The fields that differ from the golden output, old values are expected
+	// and new values are actual
+	Diffs []logtypes.FieldDiff
+	// Set if the log could not be compared, ex. its golden output is missing
+	Err error
+	// True if the golden output was written
+	Updated bool
+}
+
+// Returns true if the log matches its golden output
+func (r *LogResult) Passed() bool {
+	return r.Err == nil && len(r.Diffs) == 0
+}
+
+// Returns true if the parser ran and every log matches its golden output
+func (r *CaseResult) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, log := range r.Logs {
+		if !log.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Runs parsers with a client, ex. one of a chronicletest.Server to run them
+// against a fake
+type Runner struct {
+	Client                      *chronicleapi.Client
+	Project, Location, Instance string
+	// Rewrite the golden outputs from the actual results
+	Update bool
+	// Limits for splitting large log sets into RunParser requests
+	Batch chronicleapi.RunParserBatchOptions
+}
+
+// Returns the log types in a test data directory, sorted by log type
+func Discover(dir string) ([]*Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var cases []*Case
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		c, err := load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func load(dir string) (*Case, error) {
+	c := &Case{LogType: filepath.Base(dir), Dir: dir}
+	var err error
+	if c.Parser, err = os.ReadFile(filepath.Join(dir, parserFile)); err != nil {
+		return nil, fmt.Errorf("loading %s parser: %w", c.LogType, err)
+	}
+	if c.Extension, err = os.ReadFile(filepath.Join(dir, extensionFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading %s parser extension: %w", c.LogType, err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, logsDir))
+	if err != nil {
+		return nil, fmt.Errorf("loading %s logs: %w", c.LogType, err)
+	}
+	// log file names without their extension, which name the golden outputs
+	names := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("loading %s logs: %s and %s would share the golden output %s.json, rename one of them", c.LogType, other, entry.Name(), name)
+		}
+		names[name] = entry.Name()
+		data, err := os.ReadFile(filepath.Join(dir, logsDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("loading %s logs: %w", c.LogType, err)
+		}
+		c.Logs = append(c.Logs, Log{
+			Name: name,
+			// editors end files with a newline that is not part of the log
+			Data:   bytes.TrimRight(data, "\r\n"),
+			Golden: filepath.Join(dir, expectedDir, name+".json"),
+		})
+	}
+	return c, nil
+}
+
+// Runs the parser of every log type in a test data directory
+func (r *Runner) RunAll(ctx context.Context, dir string) ([]*CaseResult, error) {
+	cases, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*CaseResult, len(cases))
+	for i, c := range cases {
+		results[i] = r.Run(ctx, c)
+	}
+	return results, nil
+}
+
+// Runs a log type's parser against its sample logs and compares the results
+// with the golden outputs, or writes them if updating
+func (r *Runner) Run(ctx context.Context, c *Case) *CaseResult {
+	result := &CaseResult{LogType: c.LogType}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	logtype := logtypes.NewLogTypeResource(r.Project, r.Location, r.Instance, c.LogType)
+	if logtype == nil {
+		result.Err = fmt.Errorf("invalid log type %q", c.LogType)
+		return result
+	}
+	data := make([][]byte, len(c.Logs))
+	for i, log := range c.Logs {
+		data[i] = log.Data
+	}
+	resp, err := r.Client.LogTypes().RunParserBatch(ctx, logtype, c.Parser, c.Extension, data, false, r.Batch)
+	var chunkErrs chronicleapi.RunParserChunkErrors
+	if err != nil && !errors.As(err, &chunkErrs) {
+		result.Err = err
+		return result
+	}
+
+	result.Logs = make([]LogResult, len(c.Logs))
+	for i, log := range c.Logs {
+		logResult := &result.Logs[i]
+		logResult.Name, logResult.Golden = log.Name, log.Golden
+		if chunk := slices.IndexFunc(chunkErrs, func(err *chronicleapi.RunParserChunkError) bool { return i >= err.Start && i < err.End }); chunk != -1 {
+			logResult.Err = chunkErrs[chunk]
+			continue
+		}
+		actual := resp.RunParserResults[i]
+		actual.StatedumpResults = nil
+		if r.Update {
+			logResult.Err = writeGolden(log.Golden, &actual)
+			logResult.Updated = logResult.Err == nil
+			continue
+		}
+		logResult.Diffs, logResult.Err = compare(log.Golden, &actual)
+	}
+	return result
+}
+
+// Runs the parser of every log type in a test data directory as subtests
+// named by log type and log, failing the logs that do not match their golden
+// outputs. It returns the results, ex. to write a JUnit report.
+func (r *Runner) Test(t *testing.T, dir string) []*CaseResult {
+	t.Helper()
+	cases, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make([]*CaseResult, len(cases))
+	for i, c := range cases {
+		results[i] = r.Run(t.Context(), c)
+		t.Run(c.LogType, func(t *testing.T) {
+			if results[i].Err != nil {
+				t.Fatal(results[i].Err)
+			}
+			for _, log := range results[i].Logs {
+				t.Run(log.Name, func(t *testing.T) {
+					if log.Err != nil {
+						t.Fatal(log.Err)
+					}
+					if len(log.Diffs) > 0 {
+						t.Errorf("parsed output differs from %s:\n%s", log.Golden, FormatDiffs(log.Diffs))
+					}
+				})
+			}
+		})
+	}
+	return results
+}
+
+// Returns the diffs one per line
+func FormatDiffs(diffs []logtypes.FieldDiff) string {
+	lines := make([]string, len(diffs))
+	for i, diff := range diffs {
+		lines[i] = diff.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func compare(golden string, actual *logtypes.RunParserResult) ([]logtypes.FieldDiff, error) {
+	data, err := os.ReadFile(golden)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("missing golden output %s, set Runner.Update to write it", golden)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var expected interface{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, fmt.Errorf("decoding golden output %s: %w", golden, err)
+	}
+	return logtypes.Diff(expected, actual)
+}
+
+func writeGolden(golden string, actual *logtypes.RunParserResult) error {
+	data, err := json.MarshalIndent(actual, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(golden, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,161 @@
+package parsertest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/parsertest"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+}
+
+func newRunner(t *testing.T) (*chronicletest.Server, *parsertest.Runner) {
+	server := chronicletest.NewServer()
+	t.Cleanup(server.Close)
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	return server, &parsertest.Runner{Client: client, Project: "testproject", Location: "us", Instance: "testinstance"}
+}
+
+func TestUpdateFlag(t *testing.T) {
+	t.Setenv(parsertest.UpdateEnv, "1")
+	update := parsertest.UpdateFlag()
+	assert.True(t, *update)
+	assert.Same(t, update, parsertest.UpdateFlag())
+	require.NotNil(t, flag.Lookup("update"))
+	assert.Equal(t, "true", flag.Lookup("update").DefValue)
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "parser.conf"), "filter {}")
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "extension.conf"), "filter { mutate {} }")
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "logs", "login.log"), "login\n")
+	writeFile(t, filepath.Join(dir, "PAN_FIREWALL", "parser.conf"), "filter {}")
+	writeFile(t, filepath.Join(dir, "PAN_FIREWALL", "logs", "traffic.json"), `{"action": "allow"}`)
+	writeFile(t, filepath.Join(dir, "README.md"), "")
+
+	cases, err := parsertest.Discover(dir)
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+	assert.Equal(t, "PAN_FIREWALL", cases[0].LogType)
+	assert.Nil(t, cases[0].Extension)
+	winevtlog := cases[1]
+	assert.Equal(t, "WINEVTLOG", winevtlog.LogType)
+	assert.Equal(t, []byte("filter {}"), winevtlog.Parser)
+	assert.Equal(t, []byte("filter { mutate {} }"), winevtlog.Extension)
+	assert.Equal(t, []parsertest.Log{{
+		Name:   "login",
+		Data:   []byte("login"),
+		Golden: filepath.Join(dir, "WINEVTLOG", "expected", "login.json"),
+	}}, winevtlog.Logs)
+
+	// logs whose names only differ by extension would share a golden output
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "logs", "login.json"), `{"user": "alice"}`)
+	_, err = parsertest.Discover(dir)
+	assert.ErrorContains(t, err, "login.json and login.log would share the golden output login.json")
+	require.NoError(t, os.Remove(filepath.Join(dir, "WINEVTLOG", "logs", "login.json")))
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "WINEVTLOG", "parser.conf")))
+	_, err = parsertest.Discover(dir)
+	assert.Error(t, err)
+}
+
+func TestRunner(t *testing.T) {
+	server, runner := newRunner(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "parser.conf"), "filter {}")
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "logs", "a.log"), "log a")
+	writeFile(t, filepath.Join(dir, "WINEVTLOG", "logs", "b.log"), "log b")
+	ctx := context.Background()
+
+	results, err := runner.RunAll(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Logs, 2)
+	assert.ErrorContains(t, results[0].Logs[0].Err, "missing golden output")
+	assert.False(t, results[0].Passed())
+
+	runner.Update = true
+	results, err = runner.RunAll(ctx, dir)
+	require.NoError(t, err)
+	assert.True(t, results[0].Logs[0].Updated)
+	assert.FileExists(t, filepath.Join(dir, "WINEVTLOG", "expected", "a.json"))
+
+	runner.Update = false
+	results = runner.Test(t, dir)
+	assert.True(t, results[0].Passed())
+
+	server.SetParseFunc(func(cbn, cbnSnippet, log []byte, statedumpAllowed bool) logtypes.RunParserResult {
+		event := logtypes.UDM{"metadata": map[string]interface{}{"eventType": "GENERIC_EVENT", "description": "changed"}}
+		return logtypes.RunParserResult{ParsedEvents: &logtypes.ParsedEvents{Events: []logtypes.EventOrEntity{{Event: event}}}}
+	})
+	results, err = runner.RunAll(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []logtypes.FieldDiff{{
+		Path: "parsedEvents.events[0].event.metadata.description",
+		Kind: logtypes.DiffChanged,
+		Old:  "log a",
+		New:  "changed",
+	}}, results[0].Logs[0].Diffs)
+
+	server.FailNext("logTypes.runParser", 1, chronicletest.NewStatus(http.StatusBadRequest, "bad parser"))
+	results, err = runner.RunAll(ctx, dir)
+	require.NoError(t, err)
+	assert.ErrorContains(t, results[0].Logs[0].Err, "bad parser")
+	assert.ErrorContains(t, results[0].Logs[1].Err, "bad parser")
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := []*parsertest.CaseResult{{
+		LogType: "WINEVTLOG",
+		Logs: []parsertest.LogResult{
+			{Name: "a"},
+			{Name: "b", Golden: "expected/b.json", Diffs: []logtypes.FieldDiff{{Path: "principal.ip[0]", Kind: logtypes.DiffRemoved, Old: "10.0.0.1"}}},
+			{Name: "c", Err: os.ErrNotExist},
+		},
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, parsertest.WriteJUnit(&buf, results))
+
+	var report struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+					Body    string `xml:",chardata"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	require.Len(t, report.Suites, 1)
+	assert.Equal(t, "WINEVTLOG", report.Suites[0].Name)
+	require.Len(t, report.Suites[0].Cases, 3)
+	assert.Nil(t, report.Suites[0].Cases[0].Failure)
+	failure := report.Suites[0].Cases[1].Failure
+	require.NotNil(t, failure)
+	assert.Equal(t, "1 fields differ from expected/b.json", failure.Message)
+	assert.Equal(t, `- principal.ip[0]: "10.0.0.1"`, failure.Body)
+}
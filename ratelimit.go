@@ -0,0 +1,195 @@
+package chronicleapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/calebryant/chronicle-api/resources"
+)
+
+const (
+	DefaultQuotaFamily   = "default"
+	RunParserQuotaFamily = "runParser"
+)
+
+// Maps the client's API methods to the quota family they are billed
+// against. Methods not listed here belong to DefaultQuotaFamily.
+var methodQuotaFamilies = map[*apiMethod]string{
+	logTypesRunParser: RunParserQuotaFamily,
+}
+
+// Returns the quota family of an API method name, ex. "logTypes.runParser"
+func QuotaFamily(method string) string {
+	if family, ok := methodQuotaFamilies[apiMethods[method]]; ok {
+		return family
+	}
+	return DefaultQuotaFamily
+}
+
+// Blocks a call until it may be sent without exceeding a quota
+type RateLimiter interface {
+	Wait(ctx context.Context, method string, path resources.ResourcePath) error
+}
+
+// A token bucket quota: Rate requests per second refilled continuously, with
+// up to Burst requests sent back to back
+type Quota struct {
+	Rate  float64
+	Burst int
+}
+
+// Returns the quota of a family for one instance, ex. an instance with a
+// raised quota. Returning false falls back to the limiter's family quotas.
+type QuotaFunc func(instance resources.ResourcePath, family string) (Quota, bool)
+
+// Wait metrics for a single quota family
+type WaitStats struct {
+	// Number of calls that passed through the limiter
+	Calls int64
+	// Number of calls that had to wait for a token
+	Throttled int64
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// A RateLimiter with one token bucket per quota family and instance, so
+// that goroutines sharing a client stay under each instance's quota without
+// coordinating with each other
+type TokenBucketLimiter struct {
+	quotas map[string]Quota
+	// Overrides the family quotas for some instances, nil for none
+	InstanceQuota QuotaFunc
+	// Called after every call that had to wait, ex. to export wait metrics
+	OnWait func(family string, instance string, wait time.Duration)
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*tokenBucket
+	stats   map[string]*WaitStats
+}
+
+type bucketKey struct {
+	family   string
+	instance string
+}
+
+// Creates a TokenBucketLimiter from quotas keyed by quota family. Calls in a
+// family without a quota fall back to the DefaultQuotaFamily quota, and are
+// not limited if there is none. Set InstanceQuota to give some instances
+// their own quotas.
+func NewTokenBucketLimiter(quotas map[string]Quota) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		quotas:  quotas,
+		buckets: map[bucketKey]*tokenBucket{},
+		stats:   map[string]*WaitStats{},
+	}
+}
+
+func (l *TokenBucketLimiter) Wait(ctx context.Context, method string, path resources.ResourcePath) error {
+	family := QuotaFamily(method)
+	instance := path.Instance()
+	quota, ok := l.quota(instance, family)
+	if !ok || quota.Rate <= 0 {
+		return nil
+	}
+	key := bucketKey{
+		family:   family,
+		instance: instance.String(),
+	}
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(quota)
+		l.buckets[key] = bucket
+	}
+	wait := bucket.reserve(time.Now())
+	l.record(family, wait)
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if l.OnWait != nil {
+		l.OnWait(family, key.instance, wait)
+	}
+	if err := sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		bucket.cancel()
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Returns the quota of a family for an instance: the InstanceQuota override,
+// then the family quota, then the DefaultQuotaFamily quota
+func (l *TokenBucketLimiter) quota(instance resources.ResourcePath, family string) (Quota, bool) {
+	if l.InstanceQuota != nil {
+		if quota, ok := l.InstanceQuota(instance, family); ok {
+			return quota, true
+		}
+	}
+	if quota, ok := l.quotas[family]; ok {
+		return quota, true
+	}
+	quota, ok := l.quotas[DefaultQuotaFamily]
+	return quota, ok
+}
+
+// Returns a snapshot of the wait metrics keyed by quota family
+func (l *TokenBucketLimiter) Stats() map[string]WaitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := map[string]WaitStats{}
+	for family, s := range l.stats {
+		stats[family] = *s
+	}
+	return stats
+}
+
+func (l *TokenBucketLimiter) record(family string, wait time.Duration) {
+	s, ok := l.stats[family]
+	if !ok {
+		s = &WaitStats{}
+		l.stats[family] = s
+	}
+	s.Calls++
+	if wait > 0 {
+		s.Throttled++
+		s.TotalWait += wait
+		s.MaxWait = max(s.MaxWait, wait)
+	}
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(quota Quota) *tokenBucket {
+	burst := float64(max(quota.Burst, 1))
+	return &tokenBucket{
+		rate:   quota.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Takes a token and returns how long to wait until it is available. Tokens
+// may go negative, which queues waiting callers in the order they reserved.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Returns a reserved token that was never used
+func (b *tokenBucket) cancel() {
+	b.tokens = min(b.burst, b.tokens+1)
+}
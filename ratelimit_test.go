@@ -0,0 +1,109 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaFamily(t *testing.T) {
+	assert.Equal(t, chronicleapi.RunParserQuotaFamily, chronicleapi.QuotaFamily("logTypes.runParser"))
+	assert.Equal(t, chronicleapi.DefaultQuotaFamily, chronicleapi.QuotaFamily("logTypes.get"))
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	limiter := chronicleapi.NewTokenBucketLimiter(map[string]chronicleapi.Quota{
+		chronicleapi.RunParserQuotaFamily: {Rate: 100, Burst: 2},
+	})
+	var waits []time.Duration
+	limiter.OnWait = func(family, instance string, wait time.Duration) {
+		assert.Equal(t, chronicleapi.RunParserQuotaFamily, family)
+		assert.Equal(t, "projects/testproject/locations/us/instances/instance1", instance)
+		waits = append(waits, wait)
+	}
+	ctx := context.Background()
+	instance1 := resources.NewResourcePath("testproject", "us", "instance1", resources.LogtypesResourceName, "WINEVTLOG")
+	instance2 := resources.NewResourcePath("testproject", "us", "instance2", resources.LogtypesResourceName, "WINEVTLOG")
+
+	start := time.Now()
+	for range 4 {
+		require.NoError(t, limiter.Wait(ctx, "logTypes.runParser", instance1))
+	}
+	// the burst covers the first two calls, the next two wait 10ms each
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	assert.Len(t, waits, 2)
+
+	// other instances have their own bucket and unlimited families do not wait
+	require.NoError(t, limiter.Wait(ctx, "logTypes.runParser", instance2))
+	require.NoError(t, limiter.Wait(ctx, "logTypes.get", instance1))
+	assert.Len(t, waits, 2)
+
+	stats := limiter.Stats()[chronicleapi.RunParserQuotaFamily]
+	assert.Equal(t, int64(5), stats.Calls)
+	assert.Equal(t, int64(2), stats.Throttled)
+	assert.Greater(t, stats.TotalWait, time.Duration(0))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	limiter.Wait(ctx, "logTypes.runParser", instance1)
+	assert.ErrorIs(t, limiter.Wait(canceled, "logTypes.runParser", instance1), context.Canceled)
+}
+
+func TestTokenBucketLimiterInstanceQuota(t *testing.T) {
+	limiter := chronicleapi.NewTokenBucketLimiter(map[string]chronicleapi.Quota{
+		chronicleapi.RunParserQuotaFamily: {Rate: 1, Burst: 1},
+	})
+	instance1 := resources.NewResourcePath("testproject", "us", "instance1", resources.LogtypesResourceName, "WINEVTLOG")
+	instance2 := resources.NewResourcePath("testproject", "us", "instance2", resources.LogtypesResourceName, "WINEVTLOG")
+	limiter.InstanceQuota = func(instance resources.ResourcePath, family string) (chronicleapi.Quota, bool) {
+		if instance.Equal(instance2.Instance()) && family == chronicleapi.RunParserQuotaFamily {
+			return chronicleapi.Quota{Rate: 10, Burst: 5}, true
+		}
+		return chronicleapi.Quota{}, false
+	}
+	// a canceled context fails only the calls that would have to wait
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, limiter.Wait(ctx, "logTypes.runParser", instance1))
+	assert.ErrorIs(t, limiter.Wait(ctx, "logTypes.runParser", instance1), context.Canceled)
+	for range 5 {
+		require.NoError(t, limiter.Wait(ctx, "logTypes.runParser", instance2))
+	}
+	assert.ErrorIs(t, limiter.Wait(ctx, "logTypes.runParser", instance2), context.Canceled)
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	limiter := chronicleapi.NewTokenBucketLimiter(map[string]chronicleapi.Quota{
+		chronicleapi.DefaultQuotaFamily: {Rate: 50, Burst: 1},
+	})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}, chronicleapi.WithRateLimiter(limiter))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.LogTypes().Get(context.Background(), logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	// the burst admits one call and the other four need 20ms of refill each,
+	// however the goroutines are scheduled
+	assert.GreaterOrEqual(t, time.Since(start), 75*time.Millisecond)
+	stats := limiter.Stats()[chronicleapi.DefaultQuotaFamily]
+	assert.Equal(t, int64(5), stats.Calls)
+	assert.LessOrEqual(t, stats.Throttled, int64(4))
+}
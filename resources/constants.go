@@ -1,10 +1,22 @@
 package resources
 
 const (
-	ProjectsResourceName  = "projects"
-	LocationsResourceName = "locations"
-	InstancesResourceName = "instances"
-	LogtypesResourceName  = "logTypes"
-	LogsResourceName      = "logs"
-	ParsersResourceName   = "parsers"
+	ProjectsResourceName          = "projects"
+	LocationsResourceName         = "locations"
+	InstancesResourceName         = "instances"
+	LogtypesResourceName          = "logTypes"
+	LogsResourceName              = "logs"
+	ParsersResourceName           = "parsers"
+	ParserExtensionsResourceName  = "parserExtensions"
+	ValidationReportsResourceName = "validationReports"
+	ParsingErrorsResourceName     = "parsingErrors"
+	OperationsResourceName        = "operations"
+	RulesResourceName             = "rules"
+	RetrohuntsResourceName        = "retrohunts"
+	ReferenceListsResourceName    = "referenceLists"
+	FeedsResourceName             = "feeds"
 )
+
+// A resource ID that matches every resource of a collection in List methods,
+// ex. logTypes/-/parsers
+const WildcardID = "-"
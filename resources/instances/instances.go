@@ -9,11 +9,16 @@ import (
 
 // An instance API resource object
 //
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances#InstanceResource
 // https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances#InstanceResource
 type InstanceResource struct {
 	Name resources.ResourcePath `json:"name,omitempty"`
 }
 
+func NewInstanceResourceFromName(name resources.InstanceName) *InstanceResource {
+	return &InstanceResource{Name: name.Path()}
+}
+
 func NewInstanceResource(project, location, instance string) *InstanceResource {
 	if !ValidInstance(project, location, instance) {
 		return nil
@@ -29,6 +34,7 @@ func NewInstanceResource(project, location, instance string) *InstanceResource {
 
 // creates a get instance resource method http request
 //
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances/get
 // https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances/get
 func (i *InstanceResource) Get(serviceEndpoint *url.URL) (*http.Request, error) {
 	return resources.MethodRequest(
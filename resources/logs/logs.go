@@ -21,6 +21,18 @@ type LogResource struct {
 	Additionals          map[string]interface{} `json:"additionals,omitempty"`
 }
 
+// A list logs method response
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.logs/list#response-body
+type ListLogsResponse struct {
+	Logs          []LogResource `json:"logs,omitempty"`
+	NextPageToken string        `json:"nextPageToken,omitempty"`
+}
+
+func NewLogResourceFromName(name resources.LogName) *LogResource {
+	return &LogResource{Name: name.Path()}
+}
+
 func NewLogResource(project, location, instance, logtype, logVal string) *LogResource {
 	if !instances.ValidInstance(project, location, instance) || logtype == "" {
 		return nil
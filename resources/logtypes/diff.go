@@ -0,0 +1,187 @@
+package logtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// How a field differs between two values
+type DiffKind string
+
+const (
+	// The field is only set in the new value
+	DiffAdded DiffKind = "ADDED"
+	// The field is only set in the old value
+	DiffRemoved DiffKind = "REMOVED"
+	// The field is set to different values
+	DiffChanged DiffKind = "CHANGED"
+)
+
+// A field that differs between two values, ex. an expected and an actual UDM event
+type FieldDiff struct {
+	// The dotted field path, with the index of repeated fields, ex. "principal.ip[1]"
+	Path string
+	Kind DiffKind
+	// The old value, nil if the field was added
+	Old interface{}
+	// The new value, nil if the field was removed
+	New interface{}
+}
+
+func (d FieldDiff) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %s", d.Path, diffValue(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %s", d.Path, diffValue(d.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", d.Path, diffValue(d.Old), diffValue(d.New))
+}
+
+// Returns the path with the index of repeated fields removed, ex.
+// "principal.ip[1]" is "principal.ip"
+func (d FieldDiff) Field() string {
+	var field strings.Builder
+	inIndex := false
+	for _, r := range d.Path {
+		switch {
+		case r == '[':
+			inIndex = true
+		case r == ']':
+			inIndex = false
+		case !inIndex:
+			field.WriteRune(r)
+		}
+	}
+	return field.String()
+}
+
+// Returns the leaf fields that differ between two values that encode to
+// JSON, ex. two UDMs or two RunParserResults, sorted by path. Values are
+// compared as JSON, so a field set to an empty value is compared like any
+// other value and repeated fields are compared by index.
+func Diff(old, new interface{}) ([]FieldDiff, error) {
+	oldValue, err := normalize(old)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := normalize(new)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []FieldDiff
+	diffValues("", oldValue, newValue, &diffs)
+	return diffs, nil
+}
+
+// Returns the value decoded from its JSON encoding
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func diffValues(path string, old, new interface{}, diffs *[]FieldDiff) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		added(path, new, DiffAdded, diffs)
+		return
+	case new == nil:
+		added(path, old, DiffRemoved, diffs)
+		return
+	}
+	oldFields, oldIsObject := old.(map[string]interface{})
+	newFields, newIsObject := new.(map[string]interface{})
+	if oldIsObject && newIsObject {
+		keys := make([]string, 0, len(oldFields)+len(newFields))
+		for key := range oldFields {
+			keys = append(keys, key)
+		}
+		for key := range newFields {
+			if _, ok := oldFields[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			diffValues(joinPath(path, key), oldFields[key], newFields[key], diffs)
+		}
+		return
+	}
+	oldItems, oldIsArray := old.([]interface{})
+	newItems, newIsArray := new.([]interface{})
+	if oldIsArray && newIsArray {
+		for i := range max(len(oldItems), len(newItems)) {
+			var oldItem, newItem interface{}
+			if i < len(oldItems) {
+				oldItem = oldItems[i]
+			}
+			if i < len(newItems) {
+				newItem = newItems[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), oldItem, newItem, diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(old, new) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffChanged, Old: old, New: new})
+	}
+}
+
+// Reports every leaf field of a value that is only set on one side
+func added(path string, v interface{}, kind DiffKind, diffs *[]FieldDiff) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				added(joinPath(path, key), v[key], kind, diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for i, item := range v {
+				added(fmt.Sprintf("%s[%d]", path, i), item, kind, diffs)
+			}
+			return
+		}
+	}
+	diff := FieldDiff{Path: path, Kind: kind}
+	if kind == DiffAdded {
+		diff.New = v
+	} else {
+		diff.Old = v
+	}
+	*diffs = append(*diffs, diff)
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func diffValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,43 @@
+package logtypes_test
+
+import (
+	"testing"
+
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	old := logtypes.UDM{
+		"metadata":  map[string]interface{}{"eventType": "NETWORK_CONNECTION"},
+		"principal": map[string]interface{}{"ip": []string{"10.0.0.1", "10.0.0.2"}, "port": 443},
+		"target":    map[string]interface{}{"hostname": "host"},
+	}
+	new := logtypes.UDM{
+		"metadata":  map[string]interface{}{"eventType": "NETWORK_HTTP"},
+		"principal": map[string]interface{}{"ip": []interface{}{"10.0.0.1"}, "port": 443.0},
+		"network":   logtypes.UDM{"http": map[string]interface{}{"method": "GET", "responseCode": 200}},
+	}
+
+	diffs, err := logtypes.Diff(old, new)
+	require.NoError(t, err)
+	assert.Equal(t, []logtypes.FieldDiff{
+		{Path: "metadata.eventType", Kind: logtypes.DiffChanged, Old: "NETWORK_CONNECTION", New: "NETWORK_HTTP"},
+		{Path: "network.http.method", Kind: logtypes.DiffAdded, New: "GET"},
+		{Path: "network.http.responseCode", Kind: logtypes.DiffAdded, New: 200.0},
+		{Path: "principal.ip[1]", Kind: logtypes.DiffRemoved, Old: "10.0.0.2"},
+		{Path: "target.hostname", Kind: logtypes.DiffRemoved, Old: "host"},
+	}, diffs)
+	assert.Equal(t, "principal.ip", diffs[3].Field())
+	assert.Equal(t, `~ metadata.eventType: "NETWORK_CONNECTION" -> "NETWORK_HTTP"`, diffs[0].String())
+	assert.Equal(t, `+ network.http.responseCode: 200`, diffs[2].String())
+	assert.Equal(t, `- principal.ip[1]: "10.0.0.2"`, diffs[3].String())
+
+	diffs, err = logtypes.Diff(old, old)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	_, err = logtypes.Diff(old, logtypes.UDM{"bad": make(chan int)})
+	assert.Error(t, err)
+}
@@ -19,6 +19,18 @@ type LogTypeResource struct {
 	Golden      bool                   `json:"golden"`
 }
 
+// A list log types method response
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes/list#response-body
+type ListLogTypesResponse struct {
+	LogTypes      []LogTypeResource `json:"logTypes,omitempty"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+}
+
+func NewLogTypeResourceFromName(name resources.LogTypeName) *LogTypeResource {
+	return &LogTypeResource{Name: name.Path()}
+}
+
 func NewLogTypeResource(project, location, instance, logtype string) *LogTypeResource {
 	if !instances.ValidInstance(project, location, instance) {
 		return nil
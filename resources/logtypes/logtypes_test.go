@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/calebryant/chronicle-api/resources"
 	"github.com/calebryant/chronicle-api/resources/logtypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,6 +19,8 @@ func TestNewLogTypeResource(t *testing.T) {
 	testlocation := "us"
 	testinstance := "testinstance"
 	testlogtype := "WINEVTLOG"
+	logtypeName, err := resources.NewLogTypeName(testproject, testlocation, testinstance, testlogtype)
+	require.NoError(t, err)
 	tt := []struct {
 		name      string
 		value     *logtypes.LogTypeResource
@@ -47,6 +50,16 @@ func TestNewLogTypeResource(t *testing.T) {
 			expectnil: true,
 			expected:  "",
 		},
+		{
+			name:     "Lowercase logtype",
+			value:    logtypes.NewLogTypeResource(testproject, testlocation, testinstance, "winevtlog"),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/winevtlog", testproject, testlocation, testinstance),
+		},
+		{
+			name:     "From name",
+			value:    logtypes.NewLogTypeResourceFromName(logtypeName),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/%s", testproject, testlocation, testinstance, testlogtype),
+		},
 		{
 			name:     "No logtype",
 			value:    logtypes.NewLogTypeResource(testproject, testlocation, testinstance, ""),
@@ -0,0 +1,146 @@
+package logtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/calebryant/chronicle-api/resources"
+)
+
+// A run parser method response with one result per input log, in the order
+// the logs were sent
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes/runParser#response-body
+type RunParserResponse struct {
+	RunParserResults []RunParserResult `json:"runParserResults,omitempty"`
+}
+
+// The result of parsing one input log
+type RunParserResult struct {
+	// The position of the input log in the request, set by Correlate
+	Index int `json:"-"`
+	// The input log, set by Correlate
+	Log              []byte            `json:"-"`
+	ParsedEvents     *ParsedEvents     `json:"parsedEvents,omitempty"`
+	StatedumpResults []Statedump       `json:"statedumpResults,omitempty"`
+	Error            *resources.Status `json:"error,omitempty"`
+}
+
+// The UDM events and entities a log was parsed into
+type ParsedEvents struct {
+	Events []EventOrEntity `json:"events,omitempty"`
+}
+
+// A parsed UDM event or entity, only one of the fields is set
+type EventOrEntity struct {
+	Event  UDM `json:"event,omitempty"`
+	Entity UDM `json:"entity,omitempty"`
+}
+
+// The parser state dumped by a statedump filter in the parser
+type Statedump struct {
+	StatedumpResult string `json:"statedumpResult,omitempty"`
+}
+
+// A UDM event or entity as decoded JSON, ex. {"metadata": {"eventType": "NETWORK_CONNECTION"}}
+type UDM map[string]interface{}
+
+// Sets the input log and index of every result, the API returns one result
+// per input log in request order
+func (r *RunParserResponse) Correlate(logs [][]byte) error {
+	if len(r.RunParserResults) != len(logs) {
+		return fmt.Errorf("run parser returned %d results for %d logs", len(r.RunParserResults), len(logs))
+	}
+	for i := range r.RunParserResults {
+		r.RunParserResults[i].Index = i
+		r.RunParserResults[i].Log = logs[i]
+	}
+	return nil
+}
+
+// Returns the results that have an error
+func (r *RunParserResponse) Errors() []RunParserResult {
+	var failed []RunParserResult
+	for _, result := range r.RunParserResults {
+		if result.Error != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Returns the UDM events the log was parsed into, without entities
+func (r *RunParserResult) Events() []UDM {
+	if r.ParsedEvents == nil {
+		return nil
+	}
+	var events []UDM
+	for _, parsed := range r.ParsedEvents.Events {
+		if parsed.Event != nil {
+			events = append(events, parsed.Event)
+		}
+	}
+	return events
+}
+
+// Returns the first event of the event type, ex. "NETWORK_CONNECTION"
+func (r *RunParserResult) Event(eventType string) (UDM, bool) {
+	for _, event := range r.Events() {
+		if event.EventType() == eventType {
+			return event, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the metadata.eventType of an event
+func (u UDM) EventType() string {
+	eventType, _ := u.Get("metadata.eventType")
+	s, _ := eventType.(string)
+	return s
+}
+
+// Returns the value of a dotted field path, ex. "principal.ip". The path
+// uses the JSON field names of the UDM, which are lower camel case.
+func (u UDM) Get(path string) (interface{}, bool) {
+	var value interface{} = map[string]interface{}(u)
+	for _, field := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = fields[field]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Returns true if the field path is set to a non-empty value
+func (u UDM) Has(path string) bool {
+	value, ok := u.Get(path)
+	if !ok {
+		return false
+	}
+	switch value := value.(type) {
+	case nil:
+		return false
+	case string:
+		return value != ""
+	case []interface{}:
+		return len(value) != 0
+	case map[string]interface{}:
+		return len(value) != 0
+	}
+	return true
+}
+
+// Decodes the UDM into v, ex. a struct modelling the fields a test needs
+func (u UDM) Decode(v interface{}) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,64 @@
+package logtypes_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunParserResponse(t *testing.T) {
+	body := `{
+		"runParserResults": [
+			{
+				"parsedEvents": {
+					"events": [
+						{"event": {"metadata": {"eventType": "NETWORK_CONNECTION"}, "principal": {"ip": ["10.0.0.1"], "hostname": ""}}},
+						{"entity": {"metadata": {"entityType": "ASSET"}}}
+					]
+				},
+				"statedumpResults": [{"statedumpResult": "{\"message\": \"log 1\"}"}]
+			},
+			{
+				"error": {"code": 3, "message": "no match for grok pattern"}
+			}
+		]
+	}`
+	var resp logtypes.RunParserResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Error(t, resp.Correlate([][]byte{[]byte("log 1")}))
+	require.NoError(t, resp.Correlate([][]byte{[]byte("log 1"), []byte("log 2")}))
+
+	first := resp.RunParserResults[0]
+	assert.Equal(t, 0, first.Index)
+	assert.Equal(t, []byte("log 1"), first.Log)
+	require.Len(t, first.Events(), 1)
+	event, ok := first.Event("NETWORK_CONNECTION")
+	require.True(t, ok)
+	assert.True(t, event.Has("principal.ip"))
+	assert.False(t, event.Has("principal.hostname"))
+	assert.False(t, event.Has("target.ip"))
+	ip, ok := event.Get("principal.ip")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"10.0.0.1"}, ip)
+	_, ok = first.Event("USER_LOGIN")
+	assert.False(t, ok)
+	assert.Equal(t, `{"message": "log 1"}`, first.StatedumpResults[0].StatedumpResult)
+
+	var principal struct {
+		Principal struct {
+			IP []string `json:"ip"`
+		} `json:"principal"`
+	}
+	require.NoError(t, event.Decode(&principal))
+	assert.Equal(t, []string{"10.0.0.1"}, principal.Principal.IP)
+
+	failed := resp.Errors()
+	require.Len(t, failed, 1)
+	assert.Equal(t, 1, failed[0].Index)
+	assert.Equal(t, []byte("log 2"), failed[0].Log)
+	assert.Equal(t, "no match for grok pattern", failed[0].Error.Message)
+	assert.Empty(t, failed[0].Events())
+}
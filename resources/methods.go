@@ -48,6 +48,20 @@ func CreateGetRequest(endpoint *url.URL, path ResourcePath) (*http.Request, erro
 	)
 }
 
+// shared function for all REST API Delete methods
+func CreateDeleteRequest(endpoint *url.URL, path ResourcePath, query url.Values) (*http.Request, error) {
+	if !path.HasValue() {
+		return nil, fmt.Errorf("missing resource value")
+	}
+	return MethodRequest(
+		http.MethodDelete,
+		endpoint,
+		path.String(),
+		query,
+		nil,
+	)
+}
+
 // shared function for all REST API List methods
 func CreateListRequest(endpoint *url.URL, path ResourcePath, query url.Values) (*http.Request, error) {
 	return MethodRequest(
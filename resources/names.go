@@ -0,0 +1,326 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ID syntax of each collection
+var (
+	projectIDPattern       = regexp.MustCompile(`^([a-z0-9][a-z0-9.-]*:)?[a-z0-9][a-z0-9-]{0,62}$`)
+	locationIDPattern      = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	instanceIDPattern      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+	logTypeIDPattern       = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_]*$`)
+	opaqueIDPattern        = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.=-]*$`)
+	ruleIDPattern          = regexp.MustCompile(`^ru_[0-9a-f-]+(@v_[0-9]+_[0-9]+)?$`)
+	retrohuntIDPattern     = regexp.MustCompile(`^oh_[0-9a-f-]+$`)
+	referenceListIDPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,255}$`)
+)
+
+type nameSegment struct {
+	collection string
+	id         *regexp.Regexp
+}
+
+// The collections of a typed resource name, from the project down
+type nameSpec struct {
+	kind     string
+	segments []nameSegment
+}
+
+var (
+	instanceSegments = []nameSegment{
+		{ProjectsResourceName, projectIDPattern},
+		{LocationsResourceName, locationIDPattern},
+		{InstancesResourceName, instanceIDPattern},
+	}
+	logTypeSegments = append(instanceSegments[:3:3], nameSegment{LogtypesResourceName, logTypeIDPattern})
+	ruleSegments    = append(instanceSegments[:3:3], nameSegment{RulesResourceName, ruleIDPattern})
+
+	instanceNameSpec        = nameSpec{"instance", instanceSegments}
+	logTypeNameSpec         = nameSpec{"log type", logTypeSegments}
+	parserNameSpec          = nameSpec{"parser", append(logTypeSegments[:4:4], nameSegment{ParsersResourceName, opaqueIDPattern})}
+	parserExtensionNameSpec = nameSpec{"parser extension", append(logTypeSegments[:4:4], nameSegment{ParserExtensionsResourceName, opaqueIDPattern})}
+	logNameSpec             = nameSpec{"log", append(logTypeSegments[:4:4], nameSegment{LogsResourceName, opaqueIDPattern})}
+	ruleNameSpec            = nameSpec{"rule", ruleSegments}
+	retrohuntNameSpec       = nameSpec{"retrohunt", append(ruleSegments[:4:4], nameSegment{RetrohuntsResourceName, retrohuntIDPattern})}
+	referenceListNameSpec   = nameSpec{"reference list", append(instanceSegments[:3:3], nameSegment{ReferenceListsResourceName, referenceListIDPattern})}
+	feedNameSpec            = nameSpec{"feed", append(instanceSegments[:3:3], nameSegment{FeedsResourceName, opaqueIDPattern})}
+	operationNameSpec       = nameSpec{"operation", append(instanceSegments[:3:3], nameSegment{OperationsResourceName, opaqueIDPattern})}
+)
+
+// The collection of a typed resource name
+type nameKind interface {
+	spec() nameSpec
+}
+
+type (
+	instanceKind        struct{}
+	logTypeKind         struct{}
+	parserKind          struct{}
+	parserExtensionKind struct{}
+	logKind             struct{}
+	ruleKind            struct{}
+	retrohuntKind       struct{}
+	referenceListKind   struct{}
+	feedKind            struct{}
+	operationKind       struct{}
+)
+
+func (instanceKind) spec() nameSpec        { return instanceNameSpec }
+func (logTypeKind) spec() nameSpec         { return logTypeNameSpec }
+func (parserKind) spec() nameSpec          { return parserNameSpec }
+func (parserExtensionKind) spec() nameSpec { return parserExtensionNameSpec }
+func (logKind) spec() nameSpec             { return logNameSpec }
+func (ruleKind) spec() nameSpec            { return ruleNameSpec }
+func (retrohuntKind) spec() nameSpec       { return retrohuntNameSpec }
+func (referenceListKind) spec() nameSpec   { return referenceListNameSpec }
+func (feedKind) spec() nameSpec            { return feedNameSpec }
+func (operationKind) spec() nameSpec       { return operationNameSpec }
+
+// A validated resource name of one collection. The typed names embed it for
+// its methods.
+type name[K nameKind] struct {
+	path ResourcePath
+}
+
+// Builds a name from one ID per collection of the kind
+func newName[K nameKind](ids ...string) (name[K], error) {
+	var kind K
+	path, err := kind.spec().build(ids...)
+	return name[K]{path}, err
+}
+
+// Parses a name, rejecting names from any other collection
+func parseName[K nameKind](s string) (name[K], error) {
+	var kind K
+	path, err := kind.spec().parse(s)
+	return name[K]{path}, err
+}
+
+func (n name[K]) Path() ResourcePath { return n.path }
+func (n name[K]) String() string     { return n.path.String() }
+func (n name[K]) MarshalText() ([]byte, error) {
+	return n.path.MarshalText()
+}
+func (n *name[K]) UnmarshalText(text []byte) error {
+	var err error
+	*n, err = parseName[K](string(text))
+	return err
+}
+
+// Builds a resource path from one ID per collection of the spec
+func (s nameSpec) build(ids ...string) (ResourcePath, error) {
+	if len(ids) != len(s.segments) {
+		return ResourcePath{}, fmt.Errorf("%s name needs %d IDs, got %d", s.kind, len(s.segments), len(ids))
+	}
+	elements := []string{}
+	for i, segment := range s.segments {
+		if !segment.id.MatchString(ids[i]) {
+			return ResourcePath{}, fmt.Errorf("invalid %s name: invalid %s ID %q", s.kind, segment.collection, ids[i])
+		}
+		if i >= 3 {
+			elements = append(elements, segment.collection, ids[i])
+		}
+	}
+	return NewResourcePath(ids[0], ids[1], ids[2], elements...), nil
+}
+
+// Parses a resource name, rejecting names from any other collection
+func (s nameSpec) parse(name string) (ResourcePath, error) {
+	path, err := ParseResourcePath(name)
+	if err != nil {
+		return ResourcePath{}, err
+	}
+	if len(path.elements) != len(s.segments) {
+		return ResourcePath{}, fmt.Errorf("%q is not a %s name", name, s.kind)
+	}
+	ids := []string{}
+	for i, element := range path.elements {
+		if element.Name != s.segments[i].collection {
+			return ResourcePath{}, fmt.Errorf("%q is not a %s name", name, s.kind)
+		}
+		ids = append(ids, element.Value)
+	}
+	return s.build(ids...)
+}
+
+// projects/{project}/locations/{location}/instances/{instance}
+type InstanceName struct {
+	name[instanceKind]
+}
+
+func NewInstanceName(project, location, instance string) (InstanceName, error) {
+	n, err := newName[instanceKind](project, location, instance)
+	return InstanceName{n}, err
+}
+
+func ParseInstanceName(s string) (InstanceName, error) {
+	n, err := parseName[instanceKind](s)
+	return InstanceName{n}, err
+}
+
+// projects/{project}/locations/{location}/instances/{instance}/logTypes/{logtype}
+type LogTypeName struct {
+	name[logTypeKind]
+}
+
+func NewLogTypeName(project, location, instance, logtype string) (LogTypeName, error) {
+	n, err := newName[logTypeKind](project, location, instance, logtype)
+	return LogTypeName{n}, err
+}
+
+func ParseLogTypeName(s string) (LogTypeName, error) {
+	n, err := parseName[logTypeKind](s)
+	return LogTypeName{n}, err
+}
+
+func (n LogTypeName) Instance() InstanceName { return instanceName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/logTypes/{logtype}/parsers/{parser}
+type ParserName struct {
+	name[parserKind]
+}
+
+func NewParserName(project, location, instance, logtype, parser string) (ParserName, error) {
+	n, err := newName[parserKind](project, location, instance, logtype, parser)
+	return ParserName{n}, err
+}
+
+func ParseParserName(s string) (ParserName, error) {
+	n, err := parseName[parserKind](s)
+	return ParserName{n}, err
+}
+
+func (n ParserName) LogType() LogTypeName { return logTypeName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/logTypes/{logtype}/parserExtensions/{extension}
+type ParserExtensionName struct {
+	name[parserExtensionKind]
+}
+
+func NewParserExtensionName(project, location, instance, logtype, extension string) (ParserExtensionName, error) {
+	n, err := newName[parserExtensionKind](project, location, instance, logtype, extension)
+	return ParserExtensionName{n}, err
+}
+
+func ParseParserExtensionName(s string) (ParserExtensionName, error) {
+	n, err := parseName[parserExtensionKind](s)
+	return ParserExtensionName{n}, err
+}
+
+func (n ParserExtensionName) LogType() LogTypeName { return logTypeName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/logTypes/{logtype}/logs/{log}
+type LogName struct {
+	name[logKind]
+}
+
+func NewLogName(project, location, instance, logtype, log string) (LogName, error) {
+	n, err := newName[logKind](project, location, instance, logtype, log)
+	return LogName{n}, err
+}
+
+func ParseLogName(s string) (LogName, error) {
+	n, err := parseName[logKind](s)
+	return LogName{n}, err
+}
+
+func (n LogName) LogType() LogTypeName { return logTypeName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/rules/{rule}
+//
+// The rule ID may include a revision, ex. ru_{uuid}@v_{seconds}_{nanos}
+type RuleName struct {
+	name[ruleKind]
+}
+
+func NewRuleName(project, location, instance, rule string) (RuleName, error) {
+	n, err := newName[ruleKind](project, location, instance, rule)
+	return RuleName{n}, err
+}
+
+func ParseRuleName(s string) (RuleName, error) {
+	n, err := parseName[ruleKind](s)
+	return RuleName{n}, err
+}
+
+func (n RuleName) Instance() InstanceName { return instanceName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/rules/{rule}/retrohunts/{retrohunt}
+type RetrohuntName struct {
+	name[retrohuntKind]
+}
+
+func NewRetrohuntName(project, location, instance, rule, retrohunt string) (RetrohuntName, error) {
+	n, err := newName[retrohuntKind](project, location, instance, rule, retrohunt)
+	return RetrohuntName{n}, err
+}
+
+func ParseRetrohuntName(s string) (RetrohuntName, error) {
+	n, err := parseName[retrohuntKind](s)
+	return RetrohuntName{n}, err
+}
+
+func (n RetrohuntName) Rule() RuleName { return RuleName{name[ruleKind]{n.path.Parent()}} }
+
+// projects/{project}/locations/{location}/instances/{instance}/referenceLists/{referenceList}
+type ReferenceListName struct {
+	name[referenceListKind]
+}
+
+func NewReferenceListName(project, location, instance, referenceList string) (ReferenceListName, error) {
+	n, err := newName[referenceListKind](project, location, instance, referenceList)
+	return ReferenceListName{n}, err
+}
+
+func ParseReferenceListName(s string) (ReferenceListName, error) {
+	n, err := parseName[referenceListKind](s)
+	return ReferenceListName{n}, err
+}
+
+func (n ReferenceListName) Instance() InstanceName { return instanceName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/feeds/{feed}
+type FeedName struct {
+	name[feedKind]
+}
+
+func NewFeedName(project, location, instance, feed string) (FeedName, error) {
+	n, err := newName[feedKind](project, location, instance, feed)
+	return FeedName{n}, err
+}
+
+func ParseFeedName(s string) (FeedName, error) {
+	n, err := parseName[feedKind](s)
+	return FeedName{n}, err
+}
+
+func (n FeedName) Instance() InstanceName { return instanceName(n.path) }
+
+// projects/{project}/locations/{location}/instances/{instance}/operations/{operation}
+type OperationName struct {
+	name[operationKind]
+}
+
+func NewOperationName(project, location, instance, operation string) (OperationName, error) {
+	n, err := newName[operationKind](project, location, instance, operation)
+	return OperationName{n}, err
+}
+
+func ParseOperationName(s string) (OperationName, error) {
+	n, err := parseName[operationKind](s)
+	return OperationName{n}, err
+}
+
+func (n OperationName) Instance() InstanceName { return instanceName(n.path) }
+
+// Returns the instance name of a valid name below an instance
+func instanceName(path ResourcePath) InstanceName {
+	return InstanceName{name[instanceKind]{path.Instance()}}
+}
+
+// Returns the log type name of a valid name below a log type
+func logTypeName(path ResourcePath) LogTypeName {
+	return LogTypeName{name[logTypeKind]{path.Parent()}}
+}
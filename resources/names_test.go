@@ -0,0 +1,149 @@
+package resources_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNames(t *testing.T) {
+	tt := []struct {
+		name       string
+		build      func() (interface{ String() string }, error)
+		expectFail bool
+		expected   string
+	}{
+		{
+			name: "Instance",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewInstanceName("testproject", "us", "12345")
+			},
+			expected: "projects/testproject/locations/us/instances/12345",
+		},
+		{
+			name: "Domain-scoped project",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewInstanceName("google.com:testproject", "us", "12345")
+			},
+			expected: "projects/google.com:testproject/locations/us/instances/12345",
+		},
+		{
+			name: "Log type",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewLogTypeName("testproject", "us", "12345", "PAN_FIREWALL")
+			},
+			expected: "projects/testproject/locations/us/instances/12345/logTypes/PAN_FIREWALL",
+		},
+		{
+			name: "Invalid log type",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewLogTypeName("testproject", "us", "12345", "pan firewall")
+			},
+			expectFail: true,
+		},
+		{
+			name: "Parser",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewParserName("testproject", "us", "12345", "WINEVTLOG", "1234-abcd")
+			},
+			expected: "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers/1234-abcd",
+		},
+		{
+			name: "Parser wildcard",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewParserName("testproject", "us", "12345", "-", "1234-abcd")
+			},
+			expectFail: true,
+		},
+		{
+			name: "Rule with revision",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewRuleName("testproject", "us", "12345", "ru_e6abfcb5-1b85-41b0-b64c-695b3250436f@v_1680000000_123456789")
+			},
+			expected: "projects/testproject/locations/us/instances/12345/rules/ru_e6abfcb5-1b85-41b0-b64c-695b3250436f@v_1680000000_123456789",
+		},
+		{
+			name: "Invalid rule",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewRuleName("testproject", "us", "12345", "my_rule")
+			},
+			expectFail: true,
+		},
+		{
+			name: "Retrohunt",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewRetrohuntName("testproject", "us", "12345", "ru_e6abfcb5", "oh_4ae8f0b6")
+			},
+			expected: "projects/testproject/locations/us/instances/12345/rules/ru_e6abfcb5/retrohunts/oh_4ae8f0b6",
+		},
+		{
+			name: "Reference list",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewReferenceListName("testproject", "us", "12345", "admin_users")
+			},
+			expected: "projects/testproject/locations/us/instances/12345/referenceLists/admin_users",
+		},
+		{
+			name: "Invalid reference list",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewReferenceListName("testproject", "us", "12345", "admin users")
+			},
+			expectFail: true,
+		},
+		{
+			name: "Feed",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewFeedName("testproject", "us", "12345", "0d9c7d8e-2b1a")
+			},
+			expected: "projects/testproject/locations/us/instances/12345/feeds/0d9c7d8e-2b1a",
+		},
+		{
+			name: "Missing instance",
+			build: func() (interface{ String() string }, error) {
+				return resources.NewFeedName("testproject", "us", "", "0d9c7d8e-2b1a")
+			},
+			expectFail: true,
+		},
+	}
+	for _, tt := range tt {
+		value, err := tt.build()
+		if tt.expectFail {
+			assert.Error(t, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, value.String(), tt.name)
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	parserName := "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers/67890"
+	parser, err := resources.ParseParserName(parserName)
+	require.NoError(t, err)
+	assert.Equal(t, parserName, parser.String())
+	assert.Equal(t, "67890", parser.Path().ID())
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG", parser.LogType().String())
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345", parser.LogType().Instance().String())
+
+	// names from the wrong collection are rejected
+	_, err = resources.ParseRuleName(parserName)
+	assert.Error(t, err)
+	_, err = resources.ParseParserExtensionName(parserName)
+	assert.Error(t, err)
+	_, err = resources.ParseLogTypeName(parserName)
+	assert.Error(t, err)
+	_, err = resources.ParseParserName("projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers")
+	assert.Error(t, err)
+
+	ts := struct {
+		Parser resources.ParserName `json:"parser"`
+		Rule   resources.RuleName   `json:"rule"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(`{"parser": "`+parserName+`", "rule": "projects/testproject/locations/us/instances/12345/rules/ru_1234"}`), &ts))
+	assert.Equal(t, parserName, ts.Parser.String())
+	assert.Equal(t, "ru_1234", ts.Rule.Path().ID())
+	assert.Error(t, json.Unmarshal([]byte(`{"rule": "`+parserName+`"}`), &ts))
+}
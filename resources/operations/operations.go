@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/instances"
+)
+
+// A long-running operation API resource object
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations#Operation
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations#Operation
+type OperationResource struct {
+	Name     resources.ResourcePath `json:"name,omitempty"`
+	Metadata json.RawMessage        `json:"metadata,omitempty"`
+	Done     bool                   `json:"done,omitempty"`
+	Error    *resources.Status      `json:"error,omitempty"`
+	Response json.RawMessage        `json:"response,omitempty"`
+}
+
+// A list operations method response
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/list#response-body
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/list#response-body
+type ListOperationsResponse struct {
+	Operations    []OperationResource `json:"operations,omitempty"`
+	NextPageToken string              `json:"nextPageToken,omitempty"`
+}
+
+func NewOperationResourceFromName(name resources.OperationName) *OperationResource {
+	return &OperationResource{Name: name.Path()}
+}
+
+func NewOperationResource(project, location, instance, operationId string) *OperationResource {
+	if !instances.ValidInstance(project, location, instance) {
+		return nil
+	}
+	return &OperationResource{
+		Name: resources.NewResourcePath(
+			project,
+			location,
+			instance,
+			resources.OperationsResourceName,
+			operationId,
+		),
+	}
+}
+
+// creates a get operation resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/get
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/get
+func (o *OperationResource) Get(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateGetRequest(serviceEndpoint, o.Name)
+}
+
+// creates a list operations resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/list
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/list
+func (o *OperationResource) List(serviceEndpoint *url.URL, pageSize, pageToken, filter string) (*http.Request, error) {
+	return resources.CreateListRequest(
+		serviceEndpoint,
+		o.Name,
+		resources.CommonQueryParams(pageSize, pageToken, filter),
+	)
+}
+
+// creates a cancel operation resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/cancel
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/cancel
+func (o *OperationResource) Cancel(serviceEndpoint *url.URL) (*http.Request, error) {
+	if !o.Name.HasValue() {
+		return nil, fmt.Errorf("missing resource value")
+	}
+	return resources.MethodRequest(
+		http.MethodPost,
+		serviceEndpoint,
+		o.Name.String()+":cancel",
+		nil,
+		nil,
+	)
+}
+
+// creates a delete operation resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1/projects.locations.instances.operations/delete
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.operations/delete
+func (o *OperationResource) Delete(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateDeleteRequest(serviceEndpoint, o.Name, nil)
+}
@@ -0,0 +1,134 @@
+package operations_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/resources/operations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOperationResource(t *testing.T) {
+	testproject := "testproject"
+	testlocation := "us"
+	testinstance := "testinstance"
+	testoperation := "1234567890"
+	tt := []struct {
+		name      string
+		value     *operations.OperationResource
+		expectnil bool
+		expected  string
+	}{
+		{
+			name:     "Valid test",
+			value:    operations.NewOperationResource(testproject, testlocation, testinstance, testoperation),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/operations/%s", testproject, testlocation, testinstance, testoperation),
+		},
+		{
+			name:     "Valid test (no operation value)",
+			value:    operations.NewOperationResource(testproject, testlocation, testinstance, ""),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/operations", testproject, testlocation, testinstance),
+		},
+		{
+			name:      "No instance",
+			value:     operations.NewOperationResource(testproject, testlocation, "", testoperation),
+			expectnil: true,
+		},
+	}
+	for _, tt := range tt {
+		if tt.expectnil {
+			assert.Nil(t, tt.value)
+			continue
+		}
+		assert.Equal(t, tt.expected, tt.value.Name.String())
+	}
+}
+
+func TestOperationMethods(t *testing.T) {
+	testproject := "testproject"
+	testlocation := "us"
+	testinstance := "testinstance"
+	testoperation := "1234567890"
+	operationPath := fmt.Sprintf("/projects/%s/locations/%s/instances/%s/operations", testproject, testlocation, testinstance)
+	tu, _ := url.Parse("https://test.local")
+	tt := []struct {
+		name               string
+		expectFail         bool
+		value              *http.Request
+		expectedHttpMethod string
+		expectedUrlPath    string
+		expectedQuery      string
+	}{
+		{
+			name:               "Test Get Method",
+			value:              createRequest(operations.NewOperationResource(testproject, testlocation, testinstance, testoperation), "get", tu),
+			expectedHttpMethod: "GET",
+			expectedUrlPath:    operationPath + "/" + testoperation,
+		},
+		{
+			name:       "Test Get Method (no operation value)",
+			expectFail: true,
+			value:      createRequest(operations.NewOperationResource(testproject, testlocation, testinstance, ""), "get", tu),
+		},
+		{
+			name:               "Test List Method",
+			value:              createRequest(operations.NewOperationResource(testproject, testlocation, testinstance, ""), "list", tu, "10", "abcdefg", "done=true"),
+			expectedHttpMethod: "GET",
+			expectedUrlPath:    operationPath,
+			expectedQuery:      "filter=done%3Dtrue&pageSize=10&pageToken=abcdefg",
+		},
+		{
+			name:               "Test Cancel Method",
+			value:              createRequest(operations.NewOperationResource(testproject, testlocation, testinstance, testoperation), "cancel", tu),
+			expectedHttpMethod: "POST",
+			expectedUrlPath:    operationPath + "/" + testoperation + ":cancel",
+		},
+		{
+			name:       "Test Cancel Method (no operation value)",
+			expectFail: true,
+			value:      createRequest(operations.NewOperationResource(testproject, testlocation, testinstance, ""), "cancel", tu),
+		},
+		{
+			name:               "Test Delete Method",
+			value:              createRequest(operations.NewOperationResource(testproject, testlocation, testinstance, testoperation), "delete", tu),
+			expectedHttpMethod: "DELETE",
+			expectedUrlPath:    operationPath + "/" + testoperation,
+		},
+	}
+	for _, tt := range tt {
+		if tt.expectFail {
+			require.Nil(t, tt.value, tt.name)
+			continue
+		}
+		assert.Equal(t, tt.expectedUrlPath, tt.value.URL.Path, tt.name)
+		assert.Equal(t, tt.expectedQuery, tt.value.URL.Query().Encode(), tt.name)
+		assert.Equal(t, tt.expectedHttpMethod, tt.value.Method, tt.name)
+	}
+}
+
+func createRequest(resource *operations.OperationResource, methodType string, u *url.URL, options ...interface{}) *http.Request {
+	if resource == nil {
+		return nil
+	}
+	var err error
+	var req *http.Request
+	switch methodType {
+	case "get":
+		req, err = resource.Get(u)
+	case "list":
+		req, err = resource.List(u, options[0].(string), options[1].(string), options[2].(string))
+	case "cancel":
+		req, err = resource.Cancel(u)
+	case "delete":
+		req, err = resource.Delete(u)
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	return req
+}
@@ -0,0 +1,167 @@
+package parserextensions
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/instances"
+)
+
+// Parser extension states
+const (
+	StateNew           = "NEW"
+	StateValidating    = "VALIDATING"
+	StateValidated     = "VALIDATED"
+	StateRejected      = "REJECTED"
+	StateLive          = "LIVE"
+	StateArchived      = "ARCHIVED"
+	StateInternalError = "INTERNAL_ERROR"
+)
+
+// A parserExtensions API resource object. An extension is one of a CBN
+// snippet, a dynamic field mapping or a set of field extractors.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions
+type ParserExtensionResource struct {
+	Name resources.ResourcePath `json:"name,omitempty"`
+	// A CBN snippet that runs after the log type's parser
+	CbnSnippet []byte `json:"cbnSnippet,omitempty"`
+	// Raw log fields mapped into the UDM additional fields
+	DynamicParsing *DynamicParsing `json:"dynamicParsing,omitempty"`
+	// Raw log fields extracted into UDM fields
+	FieldExtractors *FieldExtractors `json:"fieldExtractors,omitempty"`
+	// A sample log the extension is validated against
+	Log                  []byte                 `json:"log,omitempty"`
+	State                string                 `json:"state,omitempty"`
+	ValidationReport     resources.ResourcePath `json:"validationReport,omitempty"`
+	StateLastChangedTime string                 `json:"stateLastChangedTime,omitempty"`
+	LastLiveTime         string                 `json:"lastLiveTime,omitempty"`
+}
+
+type DynamicParsing struct {
+	OptedFields []OptedField `json:"optedFields,omitempty"`
+}
+
+type OptedField struct {
+	Path        string `json:"path,omitempty"`
+	SampleValue string `json:"sampleValue,omitempty"`
+}
+
+type FieldExtractors struct {
+	Extractors []FieldExtractor `json:"extractors,omitempty"`
+	// The format of the raw logs, ex. "JSON", "XML", "CSV"
+	LogFormat            string `json:"logFormat,omitempty"`
+	AppendRepeatedFields bool   `json:"appendRepeatedFields,omitempty"`
+}
+
+// Copies a raw log field, or a constant value, into a UDM field when its
+// optional precondition matches
+type FieldExtractor struct {
+	PreconditionPath  string `json:"preconditionPath,omitempty"`
+	PreconditionValue string `json:"preconditionValue,omitempty"`
+	// The precondition comparison, ex. "EQUALS", "NOT_EQUALS"
+	PreconditionOp  string `json:"preconditionOp,omitempty"`
+	FieldPath       string `json:"fieldPath,omitempty"`
+	Value           string `json:"value,omitempty"`
+	DestinationPath string `json:"destinationPath,omitempty"`
+}
+
+// A list parser extensions method response
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/list#response-body
+type ListParserExtensionsResponse struct {
+	ParserExtensions []ParserExtensionResource `json:"parserExtensions,omitempty"`
+	NextPageToken    string                    `json:"nextPageToken,omitempty"`
+}
+
+func NewParserExtensionResourceFromName(name resources.ParserExtensionName) *ParserExtensionResource {
+	return &ParserExtensionResource{Name: name.Path()}
+}
+
+func NewParserExtensionResource(project, location, instance, logtype, extensionId string) *ParserExtensionResource {
+	if !instances.ValidInstance(project, location, instance) || logtype == "" {
+		return nil
+	}
+	return &ParserExtensionResource{
+		Name: resources.NewResourcePath(
+			project,
+			location,
+			instance,
+			resources.LogtypesResourceName,
+			logtype,
+			resources.ParserExtensionsResourceName,
+			extensionId,
+		),
+	}
+}
+
+// creates a get parser extension resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/get
+func (e *ParserExtensionResource) Get(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateGetRequest(serviceEndpoint, e.Name)
+}
+
+// creates a list parser extensions resource method http request. The log
+// type of the resource may be the "-" wildcard to list the extensions of
+// every log type.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/list
+func (e *ParserExtensionResource) List(serviceEndpoint *url.URL, pageSize, pageToken, filter string) (*http.Request, error) {
+	return resources.CreateListRequest(
+		serviceEndpoint,
+		e.Name,
+		resources.CommonQueryParams(pageSize, pageToken, filter),
+	)
+}
+
+// creates a create parser extension resource method http request. Exactly
+// one of CbnSnippet, DynamicParsing and FieldExtractors must be set.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/create
+func (e *ParserExtensionResource) Create(serviceEndpoint *url.URL) (*http.Request, error) {
+	body := map[string]interface{}{}
+	extensions := 0
+	if len(e.CbnSnippet) != 0 {
+		body["cbnSnippet"] = base64.StdEncoding.EncodeToString(e.CbnSnippet)
+		extensions++
+	}
+	if e.DynamicParsing != nil {
+		body["dynamicParsing"] = e.DynamicParsing
+		extensions++
+	}
+	if e.FieldExtractors != nil {
+		body["fieldExtractors"] = e.FieldExtractors
+		extensions++
+	}
+	if extensions != 1 {
+		return nil, fmt.Errorf("parser extension must have exactly one of cbnSnippet, dynamicParsing or fieldExtractors, got %d", extensions)
+	}
+	if len(e.Log) != 0 {
+		body["log"] = base64.StdEncoding.EncodeToString(e.Log)
+	}
+	return resources.MethodRequest(
+		http.MethodPost,
+		serviceEndpoint,
+		e.Name.StripLastElement(),
+		nil,
+		body,
+	)
+}
+
+// creates a delete parser extension resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/delete
+func (e *ParserExtensionResource) Delete(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateDeleteRequest(serviceEndpoint, e.Name, nil)
+}
+
+// creates an activate parser extension resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parserExtensions/activate
+func (e *ParserExtensionResource) Activate(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateActivateRequest(serviceEndpoint, e.Name)
+}
@@ -0,0 +1,191 @@
+package parserextensions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/resources/parserextensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParserExtensionResource(t *testing.T) {
+	testproject := "testproject"
+	testlocation := "us"
+	testinstance := "testinstance"
+	testlogtype := "WINEVTLOG"
+	testextensionval := "1234567890"
+	tt := []struct {
+		name       string
+		value      *parserextensions.ParserExtensionResource
+		expectfail bool
+		expected   string
+	}{
+		{
+			name:     "Valid test",
+			value:    parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, testextensionval),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/%s/parserExtensions/%s", testproject, testlocation, testinstance, testlogtype, testextensionval),
+		},
+		{
+			name:     "Valid test (no extension value)",
+			value:    parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, ""),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/%s/parserExtensions", testproject, testlocation, testinstance, testlogtype),
+		},
+		{
+			name:       "No instance",
+			value:      parserextensions.NewParserExtensionResource(testproject, testlocation, "", testlogtype, ""),
+			expectfail: true,
+		},
+		{
+			name:       "No logtype",
+			value:      parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, "", testextensionval),
+			expectfail: true,
+		},
+	}
+	for _, tt := range tt {
+		if tt.expectfail {
+			assert.Nil(t, tt.value, tt.name)
+			continue
+		}
+		assert.Equal(t, tt.expected, tt.value.Name.String(), tt.name)
+	}
+}
+
+func TestParserExtensionMethods(t *testing.T) {
+	testproject := "testproject"
+	testlocation := "us"
+	testinstance := "testinstance"
+	testlogtype := "WINEVTLOG"
+	testextensionval := "1234567890"
+	extensionPath := fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parserExtensions", testproject, testlocation, testinstance, testlogtype)
+	tu, _ := url.Parse("https://test.local")
+
+	snippet := parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, "")
+	snippet.CbnSnippet = []byte("filter {}")
+	snippet.Log = []byte("sample log")
+	extractors := parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, "")
+	extractors.FieldExtractors = &parserextensions.FieldExtractors{
+		LogFormat:  "JSON",
+		Extractors: []parserextensions.FieldExtractor{{FieldPath: "user", DestinationPath: "udm.principal.user.userid"}},
+	}
+	both := parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, "")
+	both.CbnSnippet = []byte("filter {}")
+	both.DynamicParsing = &parserextensions.DynamicParsing{OptedFields: []parserextensions.OptedField{{Path: "user"}}}
+
+	tt := []struct {
+		name               string
+		expectFail         bool
+		value              *http.Request
+		expectedHttpMethod string
+		expectedUrlPath    string
+		expectedQuery      string
+		expectedBody       map[string]interface{}
+	}{
+		{
+			name:               "Test Get Method",
+			value:              createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, testextensionval), "get", tu),
+			expectedHttpMethod: "GET",
+			expectedUrlPath:    extensionPath + "/" + testextensionval,
+		},
+		{
+			name:       "Test Get Method (no extension value)",
+			expectFail: true,
+			value:      createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, ""), "get", tu),
+		},
+		{
+			name:               "Test List Method",
+			value:              createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, ""), "list", tu, "10", "abcdefg", `state = "LIVE"`),
+			expectedHttpMethod: "GET",
+			expectedUrlPath:    extensionPath,
+			expectedQuery:      "filter=state+%3D+%22LIVE%22&pageSize=10&pageToken=abcdefg",
+		},
+		{
+			name:               "Test Create CBN Snippet Method",
+			value:              createRequest(snippet, "create", tu),
+			expectedHttpMethod: "POST",
+			expectedUrlPath:    extensionPath,
+			expectedBody:       map[string]interface{}{"cbnSnippet": "ZmlsdGVyIHt9", "log": "c2FtcGxlIGxvZw=="},
+		},
+		{
+			name:               "Test Create Field Extractors Method",
+			value:              createRequest(extractors, "create", tu),
+			expectedHttpMethod: "POST",
+			expectedUrlPath:    extensionPath,
+			expectedBody: map[string]interface{}{"fieldExtractors": map[string]interface{}{
+				"logFormat":  "JSON",
+				"extractors": []interface{}{map[string]interface{}{"fieldPath": "user", "destinationPath": "udm.principal.user.userid"}},
+			}},
+		},
+		{
+			name:       "Test Create Method (two extension types)",
+			expectFail: true,
+			value:      createRequest(both, "create", tu),
+		},
+		{
+			name:       "Test Create Method (no extension type)",
+			expectFail: true,
+			value:      createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, ""), "create", tu),
+		},
+		{
+			name:               "Test Delete Method",
+			value:              createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, testextensionval), "delete", tu),
+			expectedHttpMethod: "DELETE",
+			expectedUrlPath:    extensionPath + "/" + testextensionval,
+		},
+		{
+			name:               "Test Activate Method",
+			value:              createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, testextensionval), "activate", tu),
+			expectedHttpMethod: "POST",
+			expectedUrlPath:    extensionPath + "/" + testextensionval + ":activate",
+		},
+		{
+			name:       "Test Activate Method (no extension value)",
+			expectFail: true,
+			value:      createRequest(parserextensions.NewParserExtensionResource(testproject, testlocation, testinstance, testlogtype, ""), "activate", tu),
+		},
+	}
+	for _, tt := range tt {
+		if tt.value == nil {
+			require.True(t, tt.expectFail, tt.name)
+			continue
+		}
+		require.False(t, tt.expectFail, tt.name)
+		bodyBytes, _ := io.ReadAll(tt.value.Body)
+		var parsedBody map[string]interface{}
+		json.Unmarshal(bodyBytes, &parsedBody)
+		assert.Equal(t, tt.expectedHttpMethod, tt.value.Method, tt.name)
+		assert.Equal(t, tt.expectedUrlPath, tt.value.URL.Path, tt.name)
+		assert.Equal(t, tt.expectedQuery, tt.value.URL.Query().Encode(), tt.name)
+		assert.Equal(t, tt.expectedBody, parsedBody, tt.name)
+	}
+}
+
+func createRequest(resource *parserextensions.ParserExtensionResource, methodType string, u *url.URL, options ...string) *http.Request {
+	if resource == nil {
+		return nil
+	}
+	var err error
+	var req *http.Request
+	switch methodType {
+	case "get":
+		req, err = resource.Get(u)
+	case "list":
+		req, err = resource.List(u, options[0], options[1], options[2])
+	case "create":
+		req, err = resource.Create(u)
+	case "delete":
+		req, err = resource.Delete(u)
+	case "activate":
+		req, err = resource.Activate(u)
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	return req
+}
@@ -2,8 +2,10 @@ package parsers
 
 import (
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/calebryant/chronicle-api/resources"
 	"github.com/calebryant/chronicle-api/resources/instances"
@@ -16,17 +18,41 @@ type ParserResource struct {
 	Name                 resources.ResourcePath `json:"name,omitempty"`
 	Creator              *creator               `json:"creator,omitempty"`
 	CreateTime           string                 `json:"createTime,omitempty"`
-	Changelogs           *changelogs            `json:"changelogs,omitempty"`
+	Changelogs           *Changelogs            `json:"changelogs,omitempty"`
 	ParserExtension      string                 `json:"parserExtension,omitempty"`
 	Type                 string                 `json:"type,omitempty"`
 	State                string                 `json:"state,omitempty"`
-	ValidationReport     string                 `json:"validationReport,omitempty"`
+	ValidationReport     resources.ResourcePath `json:"validationReport,omitempty"`
 	ValidatedOnEmptyLogs bool                   `json:"validatedOnEmptyLogs,omitempty"`
 	Cbn                  []byte                 `json:"cbn,omitempty"`
 	ReleaseStage         string                 `json:"releaseStage,omitempty"`
 	ValidationStage      string                 `json:"validationStage,omitempty"`
 }
 
+// Parser states
+const (
+	StateActive   = "ACTIVE"
+	StateInactive = "INACTIVE"
+)
+
+// Parser types
+const (
+	TypeCustom   = "CUSTOM"
+	TypePrebuilt = "PREBUILT"
+)
+
+// A list parsers method response
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/list#response-body
+type ListParsersResponse struct {
+	Parsers       []ParserResource `json:"parsers,omitempty"`
+	NextPageToken string           `json:"nextPageToken,omitempty"`
+}
+
+func NewParserResourceFromName(name resources.ParserName) *ParserResource {
+	return &ParserResource{Name: name.Path()}
+}
+
 func NewParserResource(project, location, instance, logtype, parserId string) *ParserResource {
 	if !instances.ValidInstance(project, location, instance) || logtype == "" {
 		return nil
@@ -52,6 +78,67 @@ func (p *ParserResource) Deactivate(serviceEndpoint *url.URL) (*http.Request, er
 	return resources.CreateDeactivateRequest(serviceEndpoint, p.Name)
 }
 
+// creates a get parser resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/get
+func (p *ParserResource) Get(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateGetRequest(serviceEndpoint, p.Name)
+}
+
+// creates a list parsers resource method http request. The log type of the
+// resource may be the "-" wildcard to list the parsers of every log type.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/list
+func (p *ParserResource) List(serviceEndpoint *url.URL, pageSize, pageToken, filter string) (*http.Request, error) {
+	return resources.CreateListRequest(
+		serviceEndpoint,
+		p.Name,
+		resources.CommonQueryParams(pageSize, pageToken, filter),
+	)
+}
+
+// creates a delete parser resource method http request. Active parsers are
+// only deleted if force is true.
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/delete
+func (p *ParserResource) Delete(serviceEndpoint *url.URL, force bool) (*http.Request, error) {
+	query := url.Values{}
+	if force {
+		query.Set("force", "true")
+	}
+	return resources.CreateDeleteRequest(serviceEndpoint, p.Name, query)
+}
+
+// creates a copy parser resource method http request that copies a prebuilt
+// parser into a new custom parser
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers/copy
+func (p *ParserResource) Copy(serviceEndpoint *url.URL) (*http.Request, error) {
+	if !p.Name.HasValue() {
+		return nil, fmt.Errorf("missing resource value")
+	}
+	return resources.MethodRequest(
+		http.MethodPost,
+		serviceEndpoint,
+		p.Name.String()+":copy",
+		nil,
+		nil,
+	)
+}
+
+// Builds a list parsers filter that matches parsers with the state and type,
+// ex. Filter(StateActive, TypeCustom). Empty arguments match any value.
+func Filter(state, parserType string) string {
+	var clauses []string
+	if state != "" {
+		clauses = append(clauses, fmt.Sprintf("state = %q", state))
+	}
+	if parserType != "" {
+		clauses = append(clauses, fmt.Sprintf("type = %q", parserType))
+	}
+	return strings.Join(clauses, " AND ")
+}
+
 func (p *ParserResource) Create(serviceEndpoint *url.URL) (*http.Request, error) {
 	body := map[string]interface{}{
 		"cbn":                  base64.StdEncoding.EncodeToString(p.Cbn),
@@ -72,11 +159,12 @@ type creator struct {
 	Source   string `json:"source,omitempty"`
 }
 
-type changelogs struct {
-	Entries []changeEntries
+// The change history of a parser
+type Changelogs struct {
+	Entries []ChangelogEntry `json:"entries,omitempty"`
 }
 
-type changeEntries struct {
-	CreateTime    string
-	ChangeMessage string
+type ChangelogEntry struct {
+	CreateTime    string `json:"createTime,omitempty"`
+	ChangeMessage string `json:"changeMessage,omitempty"`
 }
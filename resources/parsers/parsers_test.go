@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/calebryant/chronicle-api/resources"
 	"github.com/calebryant/chronicle-api/resources/parsers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,6 +20,8 @@ func TestNewParserResource(t *testing.T) {
 	testinstance := "testinstance"
 	testlogtype := "WINEVTLOG"
 	testparserval := "1234567890"
+	parserName, err := resources.NewParserName(testproject, testlocation, testinstance, testlogtype, testparserval)
+	require.NoError(t, err)
 	tt := []struct {
 		name       string
 		value      *parsers.ParserResource
@@ -59,6 +62,16 @@ func TestNewParserResource(t *testing.T) {
 			value:      parsers.NewParserResource(testproject, testlocation, testinstance, "", ""),
 			expected:   fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/%s/parsers", testproject, testlocation, testinstance, testparserval),
 		},
+		{
+			name:     "Domain-scoped project",
+			value:    parsers.NewParserResource("google.com:testproject", testlocation, testinstance, testlogtype, testparserval),
+			expected: fmt.Sprintf("projects/google.com:testproject/locations/%s/instances/%s/logTypes/%s/parsers/%s", testlocation, testinstance, testlogtype, testparserval),
+		},
+		{
+			name:     "From name",
+			value:    parsers.NewParserResourceFromName(parserName),
+			expected: fmt.Sprintf("projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s", testproject, testlocation, testinstance, testlogtype, testparserval),
+		},
 		{
 			name:       "No logtype2",
 			expectfail: true,
@@ -108,7 +121,7 @@ func TestParserMethods(t *testing.T) {
 			name:               "Test Valid Deactivate Method",
 			value:              createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, testparserval), "deactivate", tu),
 			expectedHttpMethod: "POST",
-			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s:activate", testproject, testlocation, testinstance, testlogtype, testparserval),
+			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s:deactivate", testproject, testlocation, testinstance, testlogtype, testparserval),
 			expectedQuery:      "",
 			expectedBody:       nil,
 		},
@@ -117,6 +130,61 @@ func TestParserMethods(t *testing.T) {
 			expectFail: true,
 			value:      createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, ""), "activate", tu),
 		},
+		{
+			name:               "Test Valid Get Method",
+			value:              createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, testparserval), "get", tu),
+			expectedHttpMethod: "GET",
+			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s", testproject, testlocation, testinstance, testlogtype, testparserval),
+			expectedQuery:      "",
+			expectedBody:       nil,
+		},
+		{
+			name:       "Test Invalid Get Method",
+			expectFail: true,
+			value:      createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, ""), "get", tu),
+		},
+		{
+			name:               "Test Valid List Method",
+			value:              createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, "-", ""), "list", tu, "10", "abc", parsers.Filter(parsers.StateActive, parsers.TypeCustom)),
+			expectedHttpMethod: "GET",
+			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/-/parsers", testproject, testlocation, testinstance),
+			expectedQuery:      "filter=state+%3D+%22ACTIVE%22+AND+type+%3D+%22CUSTOM%22&pageSize=10&pageToken=abc",
+			expectedBody:       nil,
+		},
+		{
+			name:               "Test Valid Delete Method",
+			value:              createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, testparserval), "delete", tu, false),
+			expectedHttpMethod: "DELETE",
+			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s", testproject, testlocation, testinstance, testlogtype, testparserval),
+			expectedQuery:      "",
+			expectedBody:       nil,
+		},
+		{
+			name:               "Test Valid Force Delete Method",
+			value:              createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, testparserval), "delete", tu, true),
+			expectedHttpMethod: "DELETE",
+			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s", testproject, testlocation, testinstance, testlogtype, testparserval),
+			expectedQuery:      "force=true",
+			expectedBody:       nil,
+		},
+		{
+			name:       "Test Invalid Delete Method",
+			expectFail: true,
+			value:      createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, ""), "delete", tu, true),
+		},
+		{
+			name:               "Test Valid Copy Method",
+			value:              createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, testparserval), "copy", tu),
+			expectedHttpMethod: "POST",
+			expectedUrlPath:    fmt.Sprintf("/projects/%s/locations/%s/instances/%s/logTypes/%s/parsers/%s:copy", testproject, testlocation, testinstance, testlogtype, testparserval),
+			expectedQuery:      "",
+			expectedBody:       nil,
+		},
+		{
+			name:       "Test Invalid Copy Method",
+			expectFail: true,
+			value:      createRequest(parsers.NewParserResource(testproject, testlocation, testinstance, testlogtype, ""), "copy", tu),
+		},
 	}
 	for _, tt := range tt {
 		if tt.value == nil {
@@ -126,10 +194,10 @@ func TestParserMethods(t *testing.T) {
 		bodyBytes, _ := io.ReadAll(tt.value.Body)
 		parsedBody := make(map[string]interface{})
 		json.Unmarshal(bodyBytes, &parsedBody)
-		assert.Equal(t, tt.expectedUrlPath, tt.value.URL.Path)
-		assert.Equal(t, tt.expectedBody, parsedBody)
-		assert.Equal(t, tt.expectedQuery, tt.value.URL.Query().Encode())
-		assert.Equal(t, tt.expectedHttpMethod, tt.value.Method)
+		assert.Equal(t, tt.expectedUrlPath, tt.value.URL.Path, tt.name)
+		assert.Equal(t, tt.expectedBody, parsedBody, tt.name)
+		assert.Equal(t, tt.expectedQuery, tt.value.URL.Query().Encode(), tt.name)
+		assert.Equal(t, tt.expectedHttpMethod, tt.value.Method, tt.name)
 	}
 }
 
@@ -142,6 +210,16 @@ func createRequest(resource *parsers.ParserResource, methodType string, u *url.U
 	switch methodType {
 	case "activate":
 		req, err = resource.Activate(u)
+	case "deactivate":
+		req, err = resource.Deactivate(u)
+	case "get":
+		req, err = resource.Get(u)
+	case "list":
+		req, err = resource.List(u, options[0].(string), options[1].(string), options[2].(string))
+	case "delete":
+		req, err = resource.Delete(u, options[0].(bool))
+	case "copy":
+		req, err = resource.Copy(u)
 	default:
 		return nil
 	}
@@ -4,17 +4,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 type resourcePathElement struct {
-	Name   string
-	Value  string
-	parent *resourcePathElement
+	Name  string
+	Value string
 }
 
+// A Chronicle resource name, ex. projects/{project}/locations/{location}/instances/{instance}/logTypes/{logtype}
+//
+// A ResourcePath is an immutable value: methods that navigate to another
+// resource return a new ResourcePath and never modify the receiver, so a
+// path can be shared between goroutines and reused across requests.
 type ResourcePath struct {
-	resource *resourcePathElement
+	// collection/ID pairs from the project down to the resource. The slice
+	// is never written to after construction.
+	elements []resourcePathElement
 }
 
 func NewResourcePath(project, location, instance string, elements ...string) ResourcePath {
@@ -30,25 +38,86 @@ func NewResourcePath(project, location, instance string, elements ...string) Res
 		instance,
 	}
 	resourcePathSlice = append(resourcePathSlice, elements...)
-	var currentResource *resourcePathElement = nil
+	resourceElements := make([]resourcePathElement, 0, (len(resourcePathSlice)+1)/2)
 	for i, element := range resourcePathSlice {
 		// even indexes in a resource path are resource names
 		if i%2 == 0 {
-			currentResource = &resourcePathElement{
-				Name:   element,
-				parent: currentResource,
-			}
+			resourceElements = append(resourceElements, resourcePathElement{
+				Name: element,
+			})
 		} else {
-			currentResource.Value = element
+			resourceElements[len(resourceElements)-1].Value = element
 		}
 	}
 	return ResourcePath{
-		resource: currentResource,
+		elements: resourceElements,
 	}
 }
 
-func (p *ResourcePath) Resource() *resourcePathElement {
-	return p.resource
+// Returns a copy of the last element of the path, or nil if the path is empty
+func (p ResourcePath) Resource() *resourcePathElement {
+	if p.IsEmpty() {
+		return nil
+	}
+	resource := p.elements[len(p.elements)-1]
+	return &resource
+}
+
+// Parses a resource name of the form
+// projects/{project}/locations/{location}/instances/{instance}/{collection}/{id}/...
+//
+// The path may end with a collection that has no ID, ex. the parent path of
+// a List method, and an ID may be the "-" wildcard to list across every
+// resource of a collection, ex. logTypes/-/parsers.
+func ParseResourcePath(name string) (ResourcePath, error) {
+	elements := strings.Split(name, "/")
+	if len(elements) < 6 {
+		return ResourcePath{}, fmt.Errorf("invalid resource path %q: must start with projects/{project}/locations/{location}/instances/{instance}", name)
+	}
+	for i, collection := range []string{ProjectsResourceName, LocationsResourceName, InstancesResourceName} {
+		if elements[2*i] != collection {
+			return ResourcePath{}, fmt.Errorf("invalid resource path %q: element %d must be %q, got %q", name, 2*i, collection, elements[2*i])
+		}
+		if err := validateID(elements[2*i+1]); err != nil || elements[2*i+1] == WildcardID {
+			return ResourcePath{}, fmt.Errorf("invalid resource path %q: invalid %s ID %q", name, collection, elements[2*i+1])
+		}
+	}
+	for i := 6; i < len(elements); i++ {
+		// even indexes in a resource path are collection names
+		if i%2 == 0 {
+			if !validCollection(elements[i]) {
+				return ResourcePath{}, fmt.Errorf("invalid resource path %q: invalid collection %q at element %d", name, elements[i], i)
+			}
+		} else if err := validateID(elements[i]); err != nil {
+			return ResourcePath{}, fmt.Errorf("invalid resource path %q: invalid %s ID %q: %w", name, elements[i-1], elements[i], err)
+		}
+	}
+	return NewResourcePath(elements[1], elements[3], elements[5], elements[6:]...), nil
+}
+
+// Collection names are lower camel case, ex. logTypes
+func validCollection(collection string) bool {
+	if collection == "" || collection[0] < 'a' || collection[0] > 'z' {
+		return false
+	}
+	for _, r := range collection {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func validateID(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty ID")
+	}
+	for _, r := range id {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) || r == ':' {
+			return fmt.Errorf("ID contains %q", r)
+		}
+	}
+	return nil
 }
 
 func (p *ResourcePath) UnmarshalJSON(data []byte) error {
@@ -57,60 +126,125 @@ func (p *ResourcePath) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	resourcePathSlice := strings.Split(pathString, "/")
-	project := resourcePathSlice[1]
-	location := resourcePathSlice[3]
-	instance := resourcePathSlice[5]
-	theRest := resourcePathSlice[6:]
-	newResource := NewResourcePath(project, location, instance, theRest...)
-	if newResource.resource == nil {
-		return fmt.Errorf("invalid resource path %s", string(data))
+	return p.UnmarshalText([]byte(pathString))
+}
+
+func (p *ResourcePath) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = ResourcePath{}
+		return nil
+	}
+	newResource, err := ParseResourcePath(string(text))
+	if err != nil {
+		return err
 	}
 	*p = newResource
 	return nil
 }
 
-func (p *ResourcePath) String() string {
+func (p ResourcePath) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p ResourcePath) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p ResourcePath) String() string {
 	var resourceSlice []string
-	currentResource := p.resource
-	for currentResource != nil {
-		resourceSlice = append(
-			[]string{
-				currentResource.Name,
-				currentResource.Value,
-			},
-			resourceSlice...,
-		)
-		currentResource = currentResource.parent
+	for _, element := range p.elements {
+		resourceSlice = append(resourceSlice, element.Name, element.Value)
 	}
 	return path.Join(resourceSlice...)
 }
 
-func (p *ResourcePath) Map() map[string]string {
+// Returns true if the path has no elements, ex. the zero ResourcePath
+func (p ResourcePath) IsEmpty() bool {
+	return len(p.elements) == 0
+}
+
+// Returns the collection name of the last element, ex. "parsers"
+func (p ResourcePath) Collection() string {
+	if p.IsEmpty() {
+		return ""
+	}
+	return p.elements[len(p.elements)-1].Name
+}
+
+// Returns the ID of the last element, "" for a collection path
+func (p ResourcePath) ID() string {
+	if p.IsEmpty() {
+		return ""
+	}
+	return p.elements[len(p.elements)-1].Value
+}
+
+// Returns the path with the last collection/ID pair removed. The parent of
+// an instance is its location.
+func (p ResourcePath) Parent() ResourcePath {
+	if p.IsEmpty() {
+		return p
+	}
+	return ResourcePath{
+		elements: p.elements[: len(p.elements)-1 : len(p.elements)-1],
+	}
+}
+
+// Returns the path of a resource in a child collection. An empty id returns
+// the collection path, ex. the parent path of a List method.
+func (p ResourcePath) Child(collection, id string) ResourcePath {
+	if p.IsEmpty() {
+		return p
+	}
+	elements := make([]resourcePathElement, len(p.elements), len(p.elements)+1)
+	copy(elements, p.elements)
+	elements = append(elements, resourcePathElement{
+		Name:  collection,
+		Value: id,
+	})
+	return ResourcePath{
+		elements: elements,
+	}
+}
+
+// Returns the path with the ID of the last element removed
+func (p ResourcePath) WithoutID() ResourcePath {
+	if p.ID() == "" {
+		return p
+	}
+	return p.Parent().Child(p.Collection(), "")
+}
+
+// Returns the projects/{project}/locations/{location}/instances/{instance}
+// path the resource belongs to, or an empty path if there is none
+func (p ResourcePath) Instance() ResourcePath {
+	if len(p.elements) < 3 || p.elements[2].Name != InstancesResourceName {
+		return ResourcePath{}
+	}
+	return ResourcePath{
+		elements: p.elements[:3:3],
+	}
+}
+
+func (p ResourcePath) Equal(other ResourcePath) bool {
+	return slices.Equal(p.elements, other.elements)
+}
+
+// Returns the IDs of the path keyed by collection name
+func (p ResourcePath) Map() map[string]string {
 	resourceMap := map[string]string{}
-	currentResource := p.resource
-	for currentResource.parent != nil {
-		resourceMap[currentResource.Name] = currentResource.Value
+	for _, element := range p.elements {
+		resourceMap[element.Name] = element.Value
 	}
 	return resourceMap
 }
 
 // Checks the last element in a URL path. If the value is non-empty, then return the path string with the last element removed. Otherwise return the unchanged path string.
-func (p *ResourcePath) StripLastElement() string {
-	if p.resource.Value != "" {
-		newResource := *p
-		newResource.resource.Value = ""
-		return newResource.String()
-	} else {
-		return p.String()
-	}
+func (p ResourcePath) StripLastElement() string {
+	return p.WithoutID().String()
 }
 
 // Returns true if the resource value is not empty, false otherwise.
-func (p *ResourcePath) HasValue() bool {
-	if p.resource.Value != "" {
-		return true
-	} else {
-		return false
-	}
+func (p ResourcePath) HasValue() bool {
+	return p.ID() != ""
 }
@@ -2,10 +2,12 @@ package resources_test
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
 
 	"github.com/calebryant/chronicle-api/resources"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResourcePathUnmarshaJSON(t *testing.T) {
@@ -41,7 +43,151 @@ func TestResourcePathString(t *testing.T) {
 	}
 	for _, testCase := range tt {
 		path := &resources.ResourcePath{}
-		path.UnmarshalJSON([]byte(testCase.expected))
+		path.UnmarshalJSON([]byte(strconv.Quote(testCase.expected)))
 		assert.Equal(t, testCase.expected, path.String())
 	}
 }
+
+func TestParseResourcePath(t *testing.T) {
+	tt := []struct {
+		name       string
+		value      string
+		expectFail bool
+	}{
+		{
+			name:  "Instance",
+			value: "projects/testproject/locations/us/instances/123456789",
+		},
+		{
+			name:  "Parser",
+			value: "projects/testproject/locations/us/instances/123456789/logTypes/WINEVTLOG/parsers/12345",
+		},
+		{
+			name:  "Collection without ID",
+			value: "projects/testproject/locations/us/instances/123456789/logTypes/WINEVTLOG/parsers",
+		},
+		{
+			name:  "Wildcard",
+			value: "projects/testproject/locations/us/instances/123456789/logTypes/-/parsers",
+		},
+		{
+			name:       "Empty",
+			value:      "",
+			expectFail: true,
+		},
+		{
+			name:       "Too short",
+			value:      "projects/testproject/locations/us",
+			expectFail: true,
+		},
+		{
+			name:       "Wrong collection order",
+			value:      "projects/testproject/instances/123456789/locations/us",
+			expectFail: true,
+		},
+		{
+			name:       "Empty instance",
+			value:      "projects/testproject/locations/us/instances/",
+			expectFail: true,
+		},
+		{
+			name:       "Wildcard instance",
+			value:      "projects/testproject/locations/us/instances/-",
+			expectFail: true,
+		},
+		{
+			name:       "Leading slash",
+			value:      "/projects/testproject/locations/us/instances/123456789",
+			expectFail: true,
+		},
+		{
+			name:       "Trailing slash",
+			value:      "projects/testproject/locations/us/instances/123456789/logTypes/",
+			expectFail: true,
+		},
+		{
+			name:       "Empty ID",
+			value:      "projects/testproject/locations/us/instances/123456789/logTypes//parsers",
+			expectFail: true,
+		},
+		{
+			name:       "Invalid collection",
+			value:      "projects/testproject/locations/us/instances/123456789/Log Types/WINEVTLOG",
+			expectFail: true,
+		},
+		{
+			name:       "Custom method verb",
+			value:      "projects/testproject/locations/us/instances/123456789/logTypes/WINEVTLOG:runParser",
+			expectFail: true,
+		},
+	}
+	for _, tt := range tt {
+		path, err := resources.ParseResourcePath(tt.value)
+		if tt.expectFail {
+			assert.Error(t, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.value, path.String(), tt.name)
+	}
+}
+
+func TestResourcePathMarshal(t *testing.T) {
+	ts := struct {
+		Name resources.ResourcePath `json:"name"`
+	}{
+		Name: resources.NewResourcePath("testproject", "us", "12345", resources.LogtypesResourceName, "WINEVTLOG"),
+	}
+	data, err := json.Marshal(ts)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG"}`, string(data))
+
+	ts.Name = resources.ResourcePath{}
+	require.NoError(t, json.Unmarshal(data, &ts))
+	assert.Equal(t, "logTypes", ts.Name.Resource().Name)
+	assert.Equal(t, "WINEVTLOG", ts.Name.Resource().Value)
+
+	text, err := ts.Name.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG", string(text))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"name": "projects/testproject"}`), &ts))
+}
+
+func TestResourcePathNavigation(t *testing.T) {
+	instance := resources.NewResourcePath("testproject", "us", "12345")
+	parser := instance.Child(resources.LogtypesResourceName, "WINEVTLOG").Child(resources.ParsersResourceName, "67890")
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers/67890", parser.String())
+	assert.Equal(t, "parsers", parser.Collection())
+	assert.Equal(t, "67890", parser.ID())
+	assert.True(t, parser.HasValue())
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG", parser.Parent().String())
+	assert.True(t, parser.Instance().Equal(instance))
+	assert.True(t, parser.Parent().Parent().Equal(instance))
+	assert.False(t, parser.Equal(instance))
+	assert.Equal(t, map[string]string{
+		"projects":  "testproject",
+		"locations": "us",
+		"instances": "12345",
+		"logTypes":  "WINEVTLOG",
+		"parsers":   "67890",
+	}, parser.Map())
+
+	// navigating never modifies the original path
+	logtype := parser.Parent()
+	sibling := logtype.Child(resources.ParsersResourceName, "other")
+	collection := parser.WithoutID()
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers", parser.StripLastElement())
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers", collection.String())
+	assert.False(t, collection.HasValue())
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers/other", sibling.String())
+	assert.Equal(t, "projects/testproject/locations/us/instances/12345/logTypes/WINEVTLOG/parsers/67890", parser.String())
+
+	empty := resources.ResourcePath{}
+	assert.True(t, empty.IsEmpty())
+	assert.False(t, empty.HasValue())
+	assert.Nil(t, empty.Resource())
+	assert.Equal(t, "", empty.Parent().String())
+	assert.Equal(t, "", empty.Child(resources.LogtypesResourceName, "WINEVTLOG").String())
+	assert.Empty(t, empty.Map())
+}
@@ -20,6 +20,7 @@ func MethodRequest(httpMethod string, serviceEndpoint *url.URL, path string, que
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 	return req, nil
 }
 
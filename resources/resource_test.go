@@ -2,9 +2,12 @@ package resources_test
 
 import (
 	"net/url"
+	"path"
 	"testing"
 
 	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStripLastElement(t *testing.T) {
@@ -26,6 +29,11 @@ func TestStripLastElement(t *testing.T) {
 		},
 	}
 	for _, testCase := range tt {
-		testCase.input.List(testUrl, "", "")
+		name := testCase.input.Name.String()
+		req, err := testCase.input.List(testUrl, "", "")
+		require.NoError(t, err, testCase.name)
+		assert.Equal(t, testCase.expected, path.Base(req.URL.Path), testCase.name)
+		// building the request must not modify the resource
+		assert.Equal(t, name, testCase.input.Name.String(), testCase.name)
 	}
 }
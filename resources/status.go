@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	badRequestType   = "type.googleapis.com/google.rpc.BadRequest"
+	errorInfoType    = "type.googleapis.com/google.rpc.ErrorInfo"
+	retryInfoType    = "type.googleapis.com/google.rpc.RetryInfo"
+	quotaFailureType = "type.googleapis.com/google.rpc.QuotaFailure"
+)
+
+// A google.rpc.Status object, returned in API error responses and failed long-running operations
+//
+// https://cloud.google.com/apis/design/errors#error_model
+type Status struct {
+	Code    int               `json:"code,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Status  string            `json:"status,omitempty"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// A google.rpc.BadRequest error detail
+type BadRequest struct {
+	FieldViolations []FieldViolation `json:"fieldViolations,omitempty"`
+}
+
+type FieldViolation struct {
+	Field       string `json:"field,omitempty"`
+	Description string `json:"description,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// A google.rpc.ErrorInfo error detail
+type ErrorInfo struct {
+	Reason   string            `json:"reason,omitempty"`
+	Domain   string            `json:"domain,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// A google.rpc.RetryInfo error detail
+type RetryInfo struct {
+	RetryDelay string `json:"retryDelay,omitempty"`
+}
+
+// A google.rpc.QuotaFailure error detail
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations,omitempty"`
+}
+
+type QuotaViolation struct {
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Returns the BadRequest detail of the status, or nil if there is none
+func (s *Status) BadRequest() *BadRequest {
+	detail := &BadRequest{}
+	if !s.findDetail(badRequestType, detail) {
+		return nil
+	}
+	return detail
+}
+
+// Returns the ErrorInfo detail of the status, or nil if there is none
+func (s *Status) ErrorInfo() *ErrorInfo {
+	detail := &ErrorInfo{}
+	if !s.findDetail(errorInfoType, detail) {
+		return nil
+	}
+	return detail
+}
+
+// Returns the RetryInfo detail of the status, or nil if there is none
+func (s *Status) RetryInfo() *RetryInfo {
+	detail := &RetryInfo{}
+	if !s.findDetail(retryInfoType, detail) {
+		return nil
+	}
+	return detail
+}
+
+// Returns the QuotaFailure detail of the status, or nil if there is none
+func (s *Status) QuotaFailure() *QuotaFailure {
+	detail := &QuotaFailure{}
+	if !s.findDetail(quotaFailureType, detail) {
+		return nil
+	}
+	return detail
+}
+
+// Parses the retry delay, which is encoded as a JSON duration string. ex. "1.5s"
+func (r *RetryInfo) Delay() (time.Duration, bool) {
+	if r == nil || !strings.HasSuffix(r.RetryDelay, "s") {
+		return 0, false
+	}
+	delay, err := time.ParseDuration(r.RetryDelay)
+	if err != nil {
+		return 0, false
+	}
+	return delay, true
+}
+
+// Decodes the first detail of the given type into v
+func (s *Status) findDetail(detailType string, v interface{}) bool {
+	for _, detail := range s.Details {
+		typed := struct {
+			Type string `json:"@type"`
+		}{}
+		if err := json.Unmarshal(detail, &typed); err != nil || typed.Type != detailType {
+			continue
+		}
+		if err := json.Unmarshal(detail, v); err != nil {
+			return false
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package validationreports
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/instances"
+)
+
+// Validation report verdicts
+const (
+	VerdictPass = "PASS"
+	VerdictFail = "FAIL"
+)
+
+// A parser validationReports API resource object, the result of validating a
+// parser against sample logs when it is created
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports
+type ValidationReportResource struct {
+	Name    resources.ResourcePath `json:"name,omitempty"`
+	Verdict string                 `json:"verdict,omitempty"`
+	Stats   *ValidationStats       `json:"stats,omitempty"`
+	// Set if validation could not run
+	Error *resources.Status `json:"error,omitempty"`
+}
+
+// Normalization statistics of the logs a parser was validated against
+type ValidationStats struct {
+	LogEntryCount                  int64   `json:"logEntryCount,omitempty,string"`
+	SuccessfullyNormalizedLogCount int64   `json:"successfullyNormalizedLogCount,omitempty,string"`
+	FailedLogCount                 int64   `json:"failedLogCount,omitempty,string"`
+	EventCount                     int64   `json:"eventCount,omitempty,string"`
+	GenericEventCount              int64   `json:"genericEventCount,omitempty,string"`
+	NormalizationPercentage        float64 `json:"normalizationPercentage,omitempty"`
+	GenericEventPercentage         float64 `json:"genericEventPercentage,omitempty"`
+}
+
+// A parsingErrors API resource object, a log the parser failed to normalize
+// during validation
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports.parsingErrors
+type ParsingErrorResource struct {
+	Name    resources.ResourcePath `json:"name,omitempty"`
+	LogData []byte                 `json:"logData,omitempty"`
+	Error   *resources.Status      `json:"error,omitempty"`
+}
+
+// A list parsing errors method response
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports.parsingErrors/list#response-body
+type ListParsingErrorsResponse struct {
+	ParsingErrors []ParsingErrorResource `json:"parsingErrors,omitempty"`
+	NextPageToken string                 `json:"nextPageToken,omitempty"`
+}
+
+func NewValidationReportResource(project, location, instance, logtype, parser, report string) *ValidationReportResource {
+	if !instances.ValidInstance(project, location, instance) || logtype == "" || parser == "" {
+		return nil
+	}
+	return &ValidationReportResource{
+		Name: resources.NewResourcePath(
+			project,
+			location,
+			instance,
+			resources.LogtypesResourceName,
+			logtype,
+			resources.ParsersResourceName,
+			parser,
+			resources.ValidationReportsResourceName,
+			report,
+		),
+	}
+}
+
+// Returns true if the parser passed validation
+func (r *ValidationReportResource) Passed() bool {
+	return r.Verdict == VerdictPass
+}
+
+// creates a get validation report resource method http request
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports/get
+func (r *ValidationReportResource) Get(serviceEndpoint *url.URL) (*http.Request, error) {
+	return resources.CreateGetRequest(serviceEndpoint, r.Name)
+}
+
+// creates a list parsing errors resource method http request for the
+// parsingErrors sub-collection of the validation report
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports.parsingErrors/list
+func (r *ValidationReportResource) ListParsingErrors(serviceEndpoint *url.URL, pageSize, pageToken, filter string) (*http.Request, error) {
+	if !r.Name.HasValue() {
+		return nil, fmt.Errorf("missing resource value")
+	}
+	return resources.CreateListRequest(
+		serviceEndpoint,
+		r.Name.Child(resources.ParsingErrorsResourceName, ""),
+		resources.CommonQueryParams(pageSize, pageToken, filter),
+	)
+}
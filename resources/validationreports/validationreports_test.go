@@ -0,0 +1,67 @@
+package validationreports_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/calebryant/chronicle-api/resources/validationreports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidationReportResource(t *testing.T) {
+	reportPath := "projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers/12345/validationReports/abc"
+	report := validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "abc")
+	require.NotNil(t, report)
+	assert.Equal(t, reportPath, report.Name.String())
+	assert.Nil(t, validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "", "abc"))
+	assert.Nil(t, validationreports.NewValidationReportResource("testproject", "us", "", "WINEVTLOG", "12345", "abc"))
+}
+
+func TestValidationReportMethods(t *testing.T) {
+	tu, _ := url.Parse("https://test.local")
+	reportPath := "/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers/12345/validationReports"
+
+	req, err := validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "abc").Get(tu)
+	require.NoError(t, err)
+	assert.Equal(t, "GET", req.Method)
+	assert.Equal(t, reportPath+"/abc", req.URL.Path)
+
+	req, err = validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "abc").ListParsingErrors(tu, "10", "token", "")
+	require.NoError(t, err)
+	assert.Equal(t, "GET", req.Method)
+	assert.Equal(t, reportPath+"/abc/parsingErrors", req.URL.Path)
+	assert.Equal(t, "pageSize=10&pageToken=token", req.URL.Query().Encode())
+
+	_, err = validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "").Get(tu)
+	assert.Error(t, err)
+	_, err = validationreports.NewValidationReportResource("testproject", "us", "testinstance", "WINEVTLOG", "12345", "").ListParsingErrors(tu, "", "", "")
+	assert.Error(t, err)
+}
+
+func TestValidationReportJSON(t *testing.T) {
+	reportPath := "projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers/12345/validationReports/abc"
+	body := fmt.Sprintf(`{
+		"name": "%s",
+		"verdict": "FAIL",
+		"stats": {"logEntryCount": "10", "successfullyNormalizedLogCount": "8", "failedLogCount": "2", "normalizationPercentage": 80}
+	}`, reportPath)
+	var report validationreports.ValidationReportResource
+	require.NoError(t, json.Unmarshal([]byte(body), &report))
+	assert.Equal(t, reportPath, report.Name.String())
+	assert.False(t, report.Passed())
+	assert.Equal(t, int64(10), report.Stats.LogEntryCount)
+	assert.Equal(t, int64(2), report.Stats.FailedLogCount)
+	assert.Equal(t, 80.0, report.Stats.NormalizationPercentage)
+
+	var parsingError validationreports.ParsingErrorResource
+	require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{
+		"name": "%s/parsingErrors/1",
+		"logData": "YmFkIGxvZw==",
+		"error": {"code": 3, "message": "no match for grok pattern"}
+	}`, reportPath)), &parsingError))
+	assert.Equal(t, []byte("bad log"), parsingError.LogData)
+	assert.Equal(t, "no match for grok pattern", parsingError.Error.Message)
+}
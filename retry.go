@@ -0,0 +1,194 @@
+package chronicleapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultMaxElapsedTime = time.Minute
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultBackoffFactor  = 2.0
+)
+
+// An http.RoundTripper that retries idempotent requests that failed with a
+// transient error (429, 502, 503, 504 or a network error) using jittered
+// exponential backoff. Server retry hints from a RetryInfo error detail or a
+// Retry-After header are honored as the minimum wait before the next attempt.
+//
+// GET, HEAD, OPTIONS, PUT and DELETE requests are retried. POST requests are
+// only retried if their custom method verb is listed in SafeVerbs.
+type RetryTransport struct {
+	// The wrapped transport, http.DefaultTransport if nil
+	Base http.RoundTripper
+	// Maximum number of attempts, including the first one
+	MaxAttempts int
+	// Maximum time spent across all attempts and waits
+	MaxElapsedTime time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	BackoffFactor  float64
+	// POST custom method verbs that are safe to retry, ex. ":runParser"
+	SafeVerbs []string
+}
+
+// Creates a RetryTransport around base with the default retry policy
+func NewRetryTransport(base http.RoundTripper, safeVerbs ...string) *RetryTransport {
+	return &RetryTransport{
+		Base:           base,
+		MaxAttempts:    defaultMaxAttempts,
+		MaxElapsedTime: defaultMaxElapsedTime,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		BackoffFactor:  defaultBackoffFactor,
+		SafeVerbs:      safeVerbs,
+	}
+}
+
+// Returns a copy of the http client whose transport retries transient
+// failures, ex. the client returned by auth.NewClient
+func WithRetry(client *http.Client, safeVerbs ...string) *http.Client {
+	retryClient := *client
+	retryClient.Transport = NewRetryTransport(client.Transport, safeVerbs...)
+	return &retryClient
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.retry(req, t.base().RoundTrip)
+}
+
+// Returns a middleware that retries calls with the transport's retry
+// policy, for use in the client middleware chain instead of the http
+// client. Base is not used.
+func (t *RetryTransport) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			return t.retry(call.Request, func(req *http.Request) (*http.Response, error) {
+				attempt := *call
+				attempt.Request = req
+				return next(&attempt)
+			})
+		}
+	}
+}
+
+// Sends the request with send, retrying transient failures
+func (t *RetryTransport) retry(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if !t.retryable(req) {
+		return send(req)
+	}
+	start := time.Now()
+	backoff := t.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := send(attemptReq)
+		wait, retry := t.shouldRetry(req.Context(), resp, err)
+		if !retry || attempt >= t.MaxAttempts {
+			return resp, err
+		}
+		wait = max(wait, jitter(backoff))
+		if t.MaxElapsedTime > 0 && time.Since(start)+wait > t.MaxElapsedTime {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		notifyRetry(req.Context())
+		backoff = min(time.Duration(float64(backoff)*t.BackoffFactor), t.MaxBackoff)
+	}
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// Reports whether the request is idempotent and its body can be replayed
+func (t *RetryTransport) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		for _, verb := range t.SafeVerbs {
+			if strings.HasSuffix(req.URL.Path, verb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reports whether the attempt failed with a transient error and the minimum
+// time the server asked to wait before retrying. A retried response body is
+// buffered so the last response can still be returned to the caller.
+func (t *RetryTransport) shouldRetry(ctx context.Context, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		return 0, ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return 0, true
+	}
+	wait, _ := newAPIError(resp, body).RetryAfter()
+	return wait, true
+}
+
+// Returns the request to send for the given attempt, rewinding the body of
+// requests that have already been sent once
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+	return attemptReq, nil
+}
+
+// Returns a random duration between half of and the full backoff
+func jitter(backoff time.Duration) time.Duration {
+	if backoff <= 1 {
+		return backoff
+	}
+	half := backoff / 2
+	return half + rand.N(backoff-half)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
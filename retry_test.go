@@ -0,0 +1,137 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tt := []struct {
+		name             string
+		httpMethod       string
+		path             string
+		failures         int
+		failureStatus    int
+		failureBody      string
+		expectedAttempts int
+		expectedStatus   int
+	}{
+		{
+			name:             "Retry GET on 503",
+			httpMethod:       http.MethodGet,
+			path:             "logTypes/WINEVTLOG",
+			failures:         2,
+			failureStatus:    http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "Retry GET on 429 with RetryInfo",
+			httpMethod:       http.MethodGet,
+			path:             "logTypes/WINEVTLOG",
+			failures:         1,
+			failureStatus:    http.StatusTooManyRequests,
+			failureBody:      `{"error": {"code": 429, "status": "RESOURCE_EXHAUSTED", "details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "0.005s"}]}}`,
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "Give up after max attempts",
+			httpMethod:       http.MethodGet,
+			path:             "logTypes/WINEVTLOG",
+			failures:         10,
+			failureStatus:    http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:             "No retry on 404",
+			httpMethod:       http.MethodGet,
+			path:             "logTypes/WINEVTLOG",
+			failures:         1,
+			failureStatus:    http.StatusNotFound,
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "No retry on unsafe POST",
+			httpMethod:       http.MethodPost,
+			path:             "logTypes/WINEVTLOG/parsers",
+			failures:         1,
+			failureStatus:    http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:             "Retry safe POST verb",
+			httpMethod:       http.MethodPost,
+			path:             "logTypes/WINEVTLOG:runParser",
+			failures:         1,
+			failureStatus:    http.StatusServiceUnavailable,
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+	}
+	for _, tt := range tt {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			body, _ := io.ReadAll(r.Body)
+			if tt.httpMethod == http.MethodPost {
+				assert.Equal(t, `{"test":"body"}`, string(body), tt.name)
+			}
+			if attempts <= tt.failures {
+				w.WriteHeader(tt.failureStatus)
+				fmt.Fprint(w, tt.failureBody)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		}))
+		transport := chronicleapi.NewRetryTransport(server.Client().Transport, ":runParser")
+		transport.MaxAttempts = 3
+		transport.InitialBackoff = time.Millisecond
+		u, _ := url.Parse(server.URL)
+		var body map[string]interface{}
+		if tt.httpMethod == http.MethodPost {
+			body = map[string]interface{}{"test": "body"}
+		}
+		req, err := resources.MethodRequest(tt.httpMethod, u, tt.path, nil, body)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err, tt.name)
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, tt.expectedStatus, resp.StatusCode, tt.name)
+		assert.Equal(t, tt.expectedAttempts, attempts, tt.name)
+		if tt.expectedStatus != http.StatusOK {
+			assert.Equal(t, tt.failureBody, string(respBody), tt.name)
+		}
+		server.Close()
+	}
+}
+
+func TestRetryTransportContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	transport := chronicleapi.NewRetryTransport(server.Client().Transport)
+	transport.InitialBackoff = time.Hour
+	transport.MaxElapsedTime = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
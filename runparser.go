@@ -0,0 +1,180 @@
+package chronicleapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+)
+
+const (
+	DefaultRunParserMaxLogs     = 1000
+	DefaultRunParserMaxBytes    = 10 << 20
+	DefaultRunParserConcurrency = 4
+)
+
+// Limits for splitting a RunParser log set into chunks, zero values use the
+// defaults
+type RunParserBatchOptions struct {
+	// Maximum number of logs in a chunk
+	MaxLogs int
+	// Maximum encoded size of a chunk's parser, extension and logs. A log
+	// that does not fit in a chunk on its own is sent in a chunk by itself.
+	MaxBytes int
+	// Maximum number of chunks run at once
+	Concurrency int
+}
+
+// A RunParser chunk that failed
+type RunParserChunkError struct {
+	// The chunk's range of input logs, logs[Start:End]
+	Start, End int
+	Err        error
+}
+
+func (e *RunParserChunkError) Error() string {
+	return fmt.Sprintf("run parser logs [%d:%d]: %v", e.Start, e.End, e.Err)
+}
+
+func (e *RunParserChunkError) Unwrap() error {
+	return e.Err
+}
+
+// The RunParser chunks that failed, in input order
+type RunParserChunkErrors []*RunParserChunkError
+
+func (e RunParserChunkErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d run parser chunks failed: %s", len(e), strings.Join(messages, "; "))
+}
+
+func (e RunParserChunkErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Runs a parser against a log set of any size by splitting it into chunks
+// that fit the API's per-request limits, see RunParser. Chunks run
+// concurrently and their results are merged back in input order.
+//
+// If chunks fail the returned response still has one result per input log:
+// the results of a failed chunk's logs carry the chunk's error status. The
+// returned error is a RunParserChunkErrors.
+func (s *LogTypesService) RunParserBatch(ctx context.Context, logtype *logtypes.LogTypeResource, cbn, cbnSnippet []byte, logs [][]byte, statedumpAllowed bool, opts RunParserBatchOptions) (*logtypes.RunParserResponse, error) {
+	if logtype == nil {
+		return nil, fmt.Errorf("missing log type resource")
+	}
+	chunks, err := chunkLogs(logs, cbn, cbnSnippet, opts)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRunParserConcurrency
+	}
+
+	results := make([]logtypes.RunParserResult, len(logs))
+	chunkErrs := make([]*RunParserChunkError, len(chunks))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			resp, err := s.RunParser(ctx, logtype, cbn, cbnSnippet, logs[chunk.start:chunk.end], statedumpAllowed)
+			if err != nil {
+				chunkErrs[i] = &RunParserChunkError{Start: chunk.start, End: chunk.end, Err: err}
+				status := chunkStatus(err)
+				for j := chunk.start; j < chunk.end; j++ {
+					results[j] = logtypes.RunParserResult{Error: status}
+				}
+				return
+			}
+			copy(results[chunk.start:chunk.end], resp.RunParserResults)
+		}()
+	}
+	wg.Wait()
+
+	resp := &logtypes.RunParserResponse{RunParserResults: results}
+	if err := resp.Correlate(logs); err != nil {
+		return nil, err
+	}
+	var failed RunParserChunkErrors
+	for _, err := range chunkErrs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return resp, failed
+	}
+	return resp, nil
+}
+
+// A chunk of input logs, logs[start:end]
+type logChunk struct {
+	start, end int
+}
+
+// Splits logs into chunks of at most MaxLogs logs whose encoded request
+// size is at most MaxBytes
+func chunkLogs(logs [][]byte, cbn, cbnSnippet []byte, opts RunParserBatchOptions) ([]logChunk, error) {
+	maxLogs := opts.MaxLogs
+	if maxLogs <= 0 {
+		maxLogs = DefaultRunParserMaxLogs
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultRunParserMaxBytes
+	}
+	// every chunk carries the parser and extension
+	budget := maxBytes - encodedSize(cbn)
+	if len(cbnSnippet) > 0 {
+		budget -= encodedSize(cbnSnippet)
+	}
+	if budget <= 0 {
+		return nil, fmt.Errorf("parser and extension are %d bytes encoded, larger than the %d byte request limit", maxBytes-budget, maxBytes)
+	}
+	var chunks []logChunk
+	start, size := 0, 0
+	for i, log := range logs {
+		logSize := encodedSize(log)
+		if i > start && (i-start >= maxLogs || size+logSize > budget) {
+			chunks = append(chunks, logChunk{start, i})
+			start, size = i, 0
+		}
+		size += logSize
+	}
+	if start < len(logs) {
+		chunks = append(chunks, logChunk{start, len(logs)})
+	}
+	return chunks, nil
+}
+
+// Returns the size of data as a base64 JSON string with a separating comma
+func encodedSize(data []byte) int {
+	return base64.StdEncoding.EncodedLen(len(data)) + len(`"",`)
+}
+
+// Returns the status to report for the logs of a failed chunk
+func chunkStatus(err error) *resources.Status {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		status := apiErr.Status
+		return &status
+	}
+	return &resources.Status{Message: err.Error()}
+}
@@ -0,0 +1,117 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogs(count int) [][]byte {
+	logs := make([][]byte, count)
+	for i := range logs {
+		logs[i] = []byte(fmt.Sprintf("log %02d", i))
+	}
+	return logs
+}
+
+func TestRunParserBatch(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	cbn := []byte("filter {}")
+
+	tt := []struct {
+		name           string
+		logs           int
+		opts           chronicleapi.RunParserBatchOptions
+		expectedChunks int
+	}{
+		{
+			name:           "Single chunk",
+			logs:           10,
+			expectedChunks: 1,
+		},
+		{
+			name:           "Max logs",
+			logs:           25,
+			opts:           chronicleapi.RunParserBatchOptions{MaxLogs: 10, Concurrency: 2},
+			expectedChunks: 3,
+		},
+		{
+			// "filter {}" is 15 bytes encoded and each log is 11, so 4 logs fit in 64 bytes
+			name:           "Max bytes",
+			logs:           10,
+			opts:           chronicleapi.RunParserBatchOptions{MaxBytes: 64},
+			expectedChunks: 3,
+		},
+		{
+			name:           "Oversized log",
+			logs:           3,
+			opts:           chronicleapi.RunParserBatchOptions{MaxBytes: 20},
+			expectedChunks: 3,
+		},
+		{
+			name:           "No logs",
+			logs:           0,
+			expectedChunks: 0,
+		},
+	}
+	for _, tt := range tt {
+		calls := server.Calls("logTypes.runParser")
+		logs := testLogs(tt.logs)
+		resp, err := client.LogTypes().RunParserBatch(context.Background(), logtype, cbn, nil, logs, false, tt.opts)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expectedChunks, server.Calls("logTypes.runParser")-calls, tt.name)
+		require.Len(t, resp.RunParserResults, tt.logs, tt.name)
+		for i, result := range resp.RunParserResults {
+			assert.Equal(t, i, result.Index, tt.name)
+			assert.Equal(t, logs[i], result.Log, tt.name)
+			event, ok := result.Event("GENERIC_EVENT")
+			require.True(t, ok, tt.name)
+			description, _ := event.Get("metadata.description")
+			assert.Equal(t, string(logs[i]), description, tt.name)
+		}
+	}
+}
+
+func TestRunParserBatchErrors(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	client, err := server.NewClient()
+	require.NoError(t, err)
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	logs := testLogs(9)
+	ctx := context.Background()
+
+	server.FailNext("logTypes.runParser", 1, chronicletest.NewStatus(http.StatusBadRequest, "bad chunk"))
+	resp, err := client.LogTypes().RunParserBatch(ctx, logtype, []byte("filter {}"), nil, logs, false, chronicleapi.RunParserBatchOptions{MaxLogs: 3})
+	var chunkErrs chronicleapi.RunParserChunkErrors
+	require.ErrorAs(t, err, &chunkErrs)
+	require.Len(t, chunkErrs, 1)
+	chunkErr := chunkErrs[0]
+	assert.Equal(t, 3, chunkErr.End-chunkErr.Start)
+	assert.True(t, chronicleapi.IsInvalidArgument(err))
+
+	require.Len(t, resp.RunParserResults, 9)
+	failed := resp.Errors()
+	require.Len(t, failed, 3)
+	for i, result := range failed {
+		assert.Equal(t, chunkErr.Start+i, result.Index)
+		assert.Equal(t, logs[chunkErr.Start+i], result.Log)
+		assert.Equal(t, "bad chunk", result.Error.Message)
+	}
+
+	_, err = client.LogTypes().RunParserBatch(ctx, logtype, make([]byte, 64), nil, logs, false, chronicleapi.RunParserBatchOptions{MaxBytes: 64})
+	assert.Error(t, err)
+	_, err = client.LogTypes().RunParserBatch(ctx, nil, []byte("filter {}"), nil, logs, false, chronicleapi.RunParserBatchOptions{})
+	assert.Error(t, err)
+}
@@ -1,17 +1,93 @@
 package chronicleapi
 
 import (
+	"fmt"
 	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/calebryant/chronicle-api/resources"
 )
 
 const (
 	baseServiceEndpoint = "chronicle.googleapis.com"
 )
 
-// Builds a new Chronicle API service endpoint URL
+// Chronicle regions and multi-regions that have a regional service endpoint
+//
+// https://cloud.google.com/chronicle/docs/reference/rest#service-endpoint
+var Regions = []string{
+	"africa-south1",
+	"asia-northeast1",
+	"asia-south1",
+	"asia-southeast1",
+	"asia-southeast2",
+	"australia-southeast1",
+	"eu",
+	"europe-west12",
+	"europe-west2",
+	"europe-west3",
+	"europe-west6",
+	"europe-west9",
+	"me-central1",
+	"me-central2",
+	"me-west1",
+	"northamerica-northeast2",
+	"southamerica-east1",
+	"us",
+}
+
+// Builds a new Chronicle API service endpoint URL for a known region
 //
 // ex. https://us-chronicle.googleapis.com/v1alpha
-func NewServiceEndpoint(region, version string) *url.URL {
-	baseurl, _ := url.Parse("https://" + region + "-" + baseServiceEndpoint)
-	return baseurl.JoinPath(version)
+func NewServiceEndpoint(region, version string) (*url.URL, error) {
+	if !slices.Contains(Regions, region) {
+		return nil, fmt.Errorf("unknown chronicle region %q", region)
+	}
+	return NewCustomServiceEndpoint("https://"+region+"-"+baseServiceEndpoint, version)
+}
+
+// Builds a service endpoint URL from a custom base URL, ex. a Private
+// Service Connect endpoint or a local fake server
+//
+// ex. https://chronicle-psc.p.googleapis.com/v1alpha
+func NewCustomServiceEndpoint(baseURL, version string) (*url.URL, error) {
+	if version == "" {
+		return nil, fmt.Errorf("missing api version")
+	}
+	baseurl, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service endpoint %q: %w", baseURL, err)
+	}
+	if baseurl.Scheme != "https" && baseurl.Scheme != "http" {
+		return nil, fmt.Errorf("invalid service endpoint %q: scheme must be http or https", baseURL)
+	}
+	if baseurl.Host == "" {
+		return nil, fmt.Errorf("invalid service endpoint %q: missing host", baseURL)
+	}
+	return baseurl.JoinPath(version), nil
+}
+
+// Returns the region of a regional Chronicle service endpoint, or false for
+// a custom endpoint
+func EndpointRegion(serviceEndpoint *url.URL) (string, bool) {
+	region, ok := strings.CutSuffix(serviceEndpoint.Hostname(), "-"+baseServiceEndpoint)
+	if !ok || !slices.Contains(Regions, region) {
+		return "", false
+	}
+	return region, true
+}
+
+// Checks that a resource lives in the region served by a regional service
+// endpoint. Custom endpoints are not checked.
+func ValidateLocation(serviceEndpoint *url.URL, path resources.ResourcePath) error {
+	region, ok := EndpointRegion(serviceEndpoint)
+	if !ok || path.IsEmpty() {
+		return nil
+	}
+	location := path.Map()[resources.LocationsResourceName]
+	if location != region {
+		return fmt.Errorf("resource %s is in location %q but the service endpoint %s serves %q", path.String(), location, serviceEndpoint.Host, region)
+	}
+	return nil
 }
@@ -0,0 +1,82 @@
+package chronicleapi_test
+
+import (
+	"net/url"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServiceEndpoint(t *testing.T) {
+	tt := []struct {
+		name       string
+		region     string
+		version    string
+		expectFail bool
+		expected   string
+	}{
+		{
+			name:     "US",
+			region:   "us",
+			version:  "v1alpha",
+			expected: "https://us-chronicle.googleapis.com/v1alpha",
+		},
+		{
+			name:     "Regional",
+			region:   "europe-west2",
+			version:  "v1",
+			expected: "https://europe-west2-chronicle.googleapis.com/v1",
+		},
+		{
+			name:       "Unknown region",
+			region:     "moon-south1",
+			version:    "v1alpha",
+			expectFail: true,
+		},
+		{
+			name:       "Missing version",
+			region:     "us",
+			expectFail: true,
+		},
+	}
+	for _, tt := range tt {
+		endpoint, err := chronicleapi.NewServiceEndpoint(tt.region, tt.version)
+		if tt.expectFail {
+			assert.Error(t, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, endpoint.String(), tt.name)
+	}
+}
+
+func TestNewCustomServiceEndpoint(t *testing.T) {
+	endpoint, err := chronicleapi.NewCustomServiceEndpoint("http://localhost:8080", "v1alpha")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/v1alpha", endpoint.String())
+	_, ok := chronicleapi.EndpointRegion(endpoint)
+	assert.False(t, ok)
+
+	_, err = chronicleapi.NewCustomServiceEndpoint("localhost:8080", "v1alpha")
+	assert.Error(t, err)
+	_, err = chronicleapi.NewCustomServiceEndpoint("https://", "v1alpha")
+	assert.Error(t, err)
+	_, err = chronicleapi.NewCustomServiceEndpoint("https://%zz", "v1alpha")
+	assert.Error(t, err)
+}
+
+func TestValidateLocation(t *testing.T) {
+	us, _ := chronicleapi.NewServiceEndpoint("us", "v1alpha")
+	region, ok := chronicleapi.EndpointRegion(us)
+	assert.True(t, ok)
+	assert.Equal(t, "us", region)
+
+	assert.NoError(t, chronicleapi.ValidateLocation(us, resources.NewResourcePath("testproject", "us", "12345")))
+	assert.Error(t, chronicleapi.ValidateLocation(us, resources.NewResourcePath("testproject", "eu", "12345")))
+
+	custom, _ := url.Parse("https://chronicle.p.googleapis.com/v1alpha")
+	assert.NoError(t, chronicleapi.ValidateLocation(custom, resources.NewResourcePath("testproject", "eu", "12345")))
+}
@@ -0,0 +1,140 @@
+package chronicleapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/calebryant/chronicle-api/resources"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/calebryant/chronicle-api"
+
+// Span and metric attribute keys. Instance, parser ID and pages are only set
+// on spans, pages on the spans of Pager iterations.
+const (
+	MethodKey     = attribute.Key("chronicle.method")
+	InstanceKey   = attribute.Key("chronicle.instance")
+	LogTypeKey    = attribute.Key("chronicle.log_type")
+	ParserIDKey   = attribute.Key("chronicle.parser_id")
+	RetriesKey    = attribute.Key("chronicle.retries")
+	PagesKey      = attribute.Key("chronicle.pages")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+type contextKey int
+
+const (
+	retryObserverContextKey contextKey = iota
+)
+
+// Returns a context whose retried calls are reported to observer
+func withRetryObserver(ctx context.Context, observer func()) context.Context {
+	return context.WithValue(ctx, retryObserverContextKey, observer)
+}
+
+// Reports a retry of the call made with the context
+func notifyRetry(ctx context.Context) {
+	if observer, ok := ctx.Value(retryObserverContextKey).(func()); ok {
+		observer()
+	}
+}
+
+// Returns a middleware that records a span named after the API method, ex.
+// "logTypes.runParser", and call, error, retry and latency metrics for every
+// call. Nil providers fall back to the global OpenTelemetry providers.
+//
+// Add it before any retry middleware so that one span covers every attempt
+// of a call and retries are counted.
+func TelemetryMiddleware(tp trace.TracerProvider, mp metric.MeterProvider) (Middleware, error) {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	tracer := tp.Tracer(instrumentationName)
+	meter := mp.Meter(instrumentationName)
+	callCount, err := meter.Int64Counter("chronicle.client.calls", metric.WithDescription("Chronicle API calls"))
+	if err != nil {
+		return nil, err
+	}
+	errorCount, err := meter.Int64Counter("chronicle.client.errors", metric.WithDescription("Chronicle API calls that failed"))
+	if err != nil {
+		return nil, err
+	}
+	retryCount, err := meter.Int64Counter("chronicle.client.retries", metric.WithDescription("Chronicle API call retries"))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram("chronicle.client.duration", metric.WithDescription("Chronicle API call latency"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			attrs := metricAttributes(call)
+			ctx, span := tracer.Start(call.Request.Context(), call.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(spanAttributes(call)...))
+			defer span.End()
+			retries := 0
+			ctx = withRetryObserver(ctx, func() {
+				retries++
+				retryCount.Add(ctx, 1, metric.WithAttributes(attrs...))
+			})
+			call.Request = call.Request.WithContext(ctx)
+
+			start := time.Now()
+			resp, err := next(call)
+			if retries > 0 {
+				span.SetAttributes(RetriesKey.Int(retries))
+			}
+			if resp != nil {
+				attrs = append(attrs, StatusCodeKey.Int(resp.StatusCode))
+				span.SetAttributes(StatusCodeKey.Int(resp.StatusCode))
+			}
+			metricAttrs := metric.WithAttributes(attrs...)
+			duration.Record(ctx, time.Since(start).Seconds(), metricAttrs)
+			callCount.Add(ctx, 1, metricAttrs)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				errorCount.Add(ctx, 1, metricAttrs)
+			case resp.StatusCode >= 400:
+				span.SetStatus(codes.Error, resp.Status)
+				errorCount.Add(ctx, 1, metricAttrs)
+			}
+			return resp, err
+		}
+	}, nil
+}
+
+// Returns the Chronicle attributes of a call's span
+func spanAttributes(call *Call) []attribute.KeyValue {
+	attrs := metricAttributes(call)
+	if instance := call.Path.Instance(); !instance.IsEmpty() {
+		attrs = append(attrs, InstanceKey.String(instance.String()))
+	}
+	if parser := call.Path.Map()[resources.ParsersResourceName]; parser != "" {
+		attrs = append(attrs, ParserIDKey.String(parser))
+	}
+	return attrs
+}
+
+// Returns the Chronicle attributes of a call's metrics. Instances and parser
+// IDs are unbounded so they are left to spans, keeping the number of metric
+// time series bounded by the methods and log types called.
+func metricAttributes(call *Call) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		MethodKey.String(call.Method),
+	}
+	if logtype := call.Path.Map()[resources.LogtypesResourceName]; logtype != "" {
+		attrs = append(attrs, LogTypeKey.String(logtype))
+	}
+	return attrs
+}
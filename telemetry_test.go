@@ -0,0 +1,201 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/chronicletest"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/calebryant/chronicle-api/resources/parsers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// Minimal recording trace and metric providers built on the noop API implementations
+
+type testSpan struct {
+	tracenoop.Span
+	name   string
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, attr := range kv {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) SetStatus(code codes.Code, description string) { s.status = code }
+func (s *testSpan) End(...trace.SpanEndOption)                    { s.ended = true }
+
+type testTracerProvider struct {
+	tracenoop.TracerProvider
+	spans []*testSpan
+}
+
+func (p *testTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &testTracer{provider: p}
+}
+
+type testTracer struct {
+	tracenoop.Tracer
+	provider *testTracerProvider
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &testSpan{name: name, attrs: map[attribute.Key]attribute.Value{}}
+	cfg := trace.NewSpanStartConfig(opts...)
+	span.SetAttributes(cfg.Attributes()...)
+	t.provider.spans = append(t.provider.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type testCounter struct {
+	metricnoop.Int64Counter
+	mu    sync.Mutex
+	total int64
+	// the attribute keys of every recorded value
+	keys map[attribute.Key]bool
+}
+
+func (c *testCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total += incr
+	cfg := metric.NewAddConfig(opts)
+	attrs := cfg.Attributes()
+	for _, kv := range attrs.ToSlice() {
+		c.keys[kv.Key] = true
+	}
+}
+
+type testMeterProvider struct {
+	metricnoop.MeterProvider
+	counters map[string]*testCounter
+}
+
+func (p *testMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return &testMeter{provider: p}
+}
+
+type testMeter struct {
+	metricnoop.Meter
+	provider *testMeterProvider
+}
+
+func (m *testMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	counter := &testCounter{keys: map[attribute.Key]bool{}}
+	m.provider.counters[name] = counter
+	return counter, nil
+}
+
+func TestTelemetryMiddleware(t *testing.T) {
+	tp := &testTracerProvider{}
+	mp := &testMeterProvider{counters: map[string]*testCounter{}}
+	telemetry, err := chronicleapi.TelemetryMiddleware(tp, mp)
+	require.NoError(t, err)
+	retry := chronicleapi.NewRetryTransport(nil)
+	retry.InitialBackoff = time.Millisecond
+
+	attempts := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG/parsers/12345:activate":
+			w.WriteHeader(http.StatusForbidden)
+		case "/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes":
+			if r.URL.Query().Get("pageToken") == "" {
+				fmt.Fprint(w, `{"nextPageToken": "page2"}`)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		default:
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		}
+	}, chronicleapi.WithMiddleware(telemetry, retry.Middleware()))
+	ctx := context.Background()
+
+	_, err = client.LogTypes().Get(ctx, logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG"))
+	require.NoError(t, err)
+	assert.Error(t, client.Parsers().Activate(ctx, parsers.NewParserResource("testproject", "us", "testinstance", "WINEVTLOG", "12345")))
+	_, err = client.LogTypes().Pager(logtypes.NewLogTypeResource("testproject", "us", "testinstance", ""), "").Collect(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, tp.spans, 4)
+	get := tp.spans[0]
+	assert.Equal(t, "logTypes.get", get.name)
+	assert.True(t, get.ended)
+	assert.Equal(t, "projects/testproject/locations/us/instances/testinstance", get.attrs[chronicleapi.InstanceKey].AsString())
+	assert.Equal(t, "WINEVTLOG", get.attrs[chronicleapi.LogTypeKey].AsString())
+	assert.Equal(t, int64(200), get.attrs[chronicleapi.StatusCodeKey].AsInt64())
+	assert.Equal(t, int64(1), get.attrs[chronicleapi.RetriesKey].AsInt64())
+	assert.Equal(t, codes.Unset, get.status)
+
+	activate := tp.spans[1]
+	assert.Equal(t, "parsers.activate", activate.name)
+	assert.Equal(t, "12345", activate.attrs[chronicleapi.ParserIDKey].AsString())
+	assert.Equal(t, int64(403), activate.attrs[chronicleapi.StatusCodeKey].AsInt64())
+	assert.Equal(t, codes.Error, activate.status)
+
+	assert.Equal(t, "logTypes.list", tp.spans[2].name)
+	assert.Equal(t, "logTypes.list", tp.spans[3].name)
+
+	calls := mp.counters["chronicle.client.calls"]
+	assert.Equal(t, int64(4), calls.total)
+	// unbounded attributes are left off metrics
+	assert.Equal(t, map[attribute.Key]bool{chronicleapi.MethodKey: true, chronicleapi.LogTypeKey: true, chronicleapi.StatusCodeKey: true}, calls.keys)
+	assert.Equal(t, int64(1), mp.counters["chronicle.client.errors"].total)
+	assert.Equal(t, int64(1), mp.counters["chronicle.client.retries"].total)
+}
+
+func TestPagerTelemetry(t *testing.T) {
+	server := chronicletest.NewServer()
+	defer server.Close()
+	for i := range 5 {
+		require.NoError(t, server.Add(logtypes.NewLogTypeResource("testproject", "us", "testinstance", fmt.Sprintf("LOGTYPE_%d", i))))
+	}
+	tp := &testTracerProvider{}
+	telemetry, err := chronicleapi.TelemetryMiddleware(tp, nil)
+	require.NoError(t, err)
+	client, err := server.NewClient(chronicleapi.WithMiddleware(telemetry))
+	require.NoError(t, err)
+
+	pager := client.LogTypes().Pager(logtypes.NewLogTypeResource("testproject", "us", "testinstance", ""), "2")
+	pager.TracerProvider = tp
+	listed, err := pager.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, listed, 5)
+
+	require.Len(t, tp.spans, 4)
+	pages := tp.spans[0]
+	assert.Equal(t, "logTypes.list pages", pages.name)
+	assert.True(t, pages.ended)
+	assert.Equal(t, int64(3), pages.attrs[chronicleapi.PagesKey].AsInt64())
+	assert.Equal(t, "logTypes.list", pages.attrs[chronicleapi.MethodKey].AsString())
+	for _, span := range tp.spans[1:] {
+		assert.Equal(t, "logTypes.list", span.name)
+	}
+
+	// an iteration stopped early counts the pages it fetched
+	pager.MaxItems = 1
+	_, err = pager.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), tp.spans[4].attrs[chronicleapi.PagesKey].AsInt64())
+}
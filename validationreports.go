@@ -0,0 +1,62 @@
+package chronicleapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/calebryant/chronicle-api/resources/validationreports"
+)
+
+// Executes parser and parser extension validationReports resource methods
+type ValidationReportsService struct {
+	client *Client
+}
+
+var validationReportsGet = newAPIMethod("validationReports.get", V1Alpha)
+
+// Gets a parser or parser extension validation report, ex. the report named
+// by a parser's ValidationReport field
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports/get
+func (s *ValidationReportsService) Get(ctx context.Context, report *validationreports.ValidationReportResource) (*validationreports.ValidationReportResource, error) {
+	if report == nil {
+		return nil, fmt.Errorf("missing validation report resource")
+	}
+	result := &validationreports.ValidationReportResource{}
+	if err := s.client.do(ctx, validationReportsGet, report.Name, report.Get, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var parsingErrorsList = newAPIMethod("parsingErrors.list", V1Alpha)
+
+// Lists a single page of the logs that failed to parse during validation
+//
+// https://cloud.google.com/chronicle/docs/reference/rest/v1alpha/projects.locations.instances.logTypes.parsers.validationReports.parsingErrors/list
+func (s *ValidationReportsService) ListParsingErrors(ctx context.Context, report *validationreports.ValidationReportResource, pageSize, pageToken, filter string) (*validationreports.ListParsingErrorsResponse, error) {
+	if report == nil {
+		return nil, fmt.Errorf("missing validation report resource")
+	}
+	result := &validationreports.ListParsingErrorsResponse{}
+	build := func(endpoint *url.URL) (*http.Request, error) {
+		return report.ListParsingErrors(endpoint, pageSize, pageToken, filter)
+	}
+	if err := s.client.do(ctx, parsingErrorsList, report.Name, build, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a pager over every parsing error of the validation report
+func (s *ValidationReportsService) ParsingErrorsPager(report *validationreports.ValidationReportResource, pageSize, filter string) *Pager[validationreports.ParsingErrorResource] {
+	return newListPager(parsingErrorsList, func(ctx context.Context, pageToken string) ([]validationreports.ParsingErrorResource, string, error) {
+		resp, err := s.ListParsingErrors(ctx, report, pageSize, pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.ParsingErrors, resp.NextPageToken, nil
+	})
+}
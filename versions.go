@@ -0,0 +1,77 @@
+package chronicleapi
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+)
+
+// A Chronicle API version, the first path element of every method URL
+type APIVersion string
+
+const (
+	V1      APIVersion = "v1"
+	V1Alpha APIVersion = "v1alpha"
+)
+
+// Every known API version, from most to least stable
+var APIVersions = []APIVersion{V1, V1Alpha}
+
+// An API method, ex. "logTypes.runParser", declared next to the service
+// method that calls it
+type apiMethod struct {
+	name string
+	// The versions that support the method, from most to least stable
+	versions []APIVersion
+}
+
+// Every declared method, by name
+var apiMethods = map[string]*apiMethod{}
+
+// Declares a method and the API versions that support it
+func newAPIMethod(name string, versions ...APIVersion) *apiMethod {
+	method := &apiMethod{name: name, versions: versions}
+	apiMethods[name] = method
+	return method
+}
+
+// Returns the API versions that support a method, ex. "logTypes.runParser".
+// Unknown methods are only available in v1alpha.
+func MethodVersions(method string) []APIVersion {
+	if m, ok := apiMethods[method]; ok {
+		return m.versions
+	}
+	return []APIVersion{V1Alpha}
+}
+
+// Returns the first of the client's API versions that supports the method
+func (c *Client) version(method *apiMethod) (APIVersion, error) {
+	for _, version := range c.versions {
+		if slices.Contains(method.versions, version) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("%s is only available in api versions %v, the client is limited to %v", method.name, method.versions, c.versions)
+}
+
+// Matches path segments that name an API version, ex. "v2" or "v1beta1"
+var versionPattern = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]*)?$`)
+
+// Splits a service endpoint into its base URL and API version. An endpoint
+// without a version may call every known version, one ending in an unknown
+// version is rejected.
+func splitVersion(serviceEndpoint *url.URL) (*url.URL, []APIVersion, error) {
+	version := APIVersion(path.Base(serviceEndpoint.Path))
+	if !slices.Contains(APIVersions, version) {
+		if versionPattern.MatchString(string(version)) {
+			return nil, nil, fmt.Errorf("unknown api version %q in service endpoint %s, known versions are %v", version, serviceEndpoint, APIVersions)
+		}
+		return serviceEndpoint, slices.Clone(APIVersions), nil
+	}
+	baseEndpoint := *serviceEndpoint
+	baseEndpoint.Path = path.Dir(serviceEndpoint.Path)
+	baseEndpoint.RawPath = ""
+	return &baseEndpoint, []APIVersion{version}, nil
+}
@@ -0,0 +1,89 @@
+package chronicleapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	chronicleapi "github.com/calebryant/chronicle-api"
+	"github.com/calebryant/chronicle-api/resources/instances"
+	"github.com/calebryant/chronicle-api/resources/logtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodVersions(t *testing.T) {
+	assert.Equal(t, []chronicleapi.APIVersion{chronicleapi.V1, chronicleapi.V1Alpha}, chronicleapi.MethodVersions("instances.get"))
+	assert.Equal(t, []chronicleapi.APIVersion{chronicleapi.V1Alpha}, chronicleapi.MethodVersions("logTypes.runParser"))
+}
+
+func TestClientAPIVersions(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+	base, _ := url.Parse(server.URL)
+	instance := instances.NewInstanceResource("testproject", "us", "testinstance")
+	logtype := logtypes.NewLogTypeResource("testproject", "us", "testinstance", "WINEVTLOG")
+	ctx := context.Background()
+	tt := []struct {
+		name          string
+		endpoint      *url.URL
+		opts          []chronicleapi.ClientOption
+		expectedPaths []string
+		expectFail    bool
+	}{
+		{
+			name:     "Endpoint pinned to v1alpha",
+			endpoint: base.JoinPath("v1alpha"),
+			expectedPaths: []string{
+				"/v1alpha/projects/testproject/locations/us/instances/testinstance",
+				"/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG",
+			},
+		},
+		{
+			name:     "Endpoint without version prefers v1",
+			endpoint: base,
+			expectedPaths: []string{
+				"/v1/projects/testproject/locations/us/instances/testinstance",
+				"/v1alpha/projects/testproject/locations/us/instances/testinstance/logTypes/WINEVTLOG",
+			},
+		},
+		{
+			name:     "Stable versions only",
+			endpoint: base,
+			opts:     []chronicleapi.ClientOption{chronicleapi.WithAPIVersions(chronicleapi.V1)},
+			expectedPaths: []string{
+				"/v1/projects/testproject/locations/us/instances/testinstance",
+			},
+			expectFail: true,
+		},
+	}
+	for _, tt := range tt {
+		paths = nil
+		client, err := chronicleapi.NewClient(server.Client(), tt.endpoint, tt.opts...)
+		require.NoError(t, err, tt.name)
+		_, err = client.Instances().Get(ctx, instance)
+		require.NoError(t, err, tt.name)
+		_, err = client.LogTypes().Get(ctx, logtype)
+		if tt.expectFail {
+			assert.ErrorContains(t, err, "logTypes.get is only available in api versions [v1alpha]", tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+		assert.Equal(t, tt.expectedPaths, paths, tt.name)
+	}
+
+	// an unknown version is not mistaken for part of the base URL
+	_, err := chronicleapi.NewClient(server.Client(), base.JoinPath("v2"))
+	assert.ErrorContains(t, err, `unknown api version "v2"`)
+	_, err = chronicleapi.NewClient(server.Client(), base.JoinPath("v1beta"))
+	assert.Error(t, err)
+	_, err = chronicleapi.NewClient(server.Client(), base.JoinPath("chronicle"))
+	assert.NoError(t, err)
+}